@@ -161,7 +161,7 @@ func setCert(node *yaml.Node, cert *configtypes.Cert) (persist bool, err error)
 			if err != nil {
 				return false, err
 			}
-			persist, err = nodeutils.MergeNodes(newCertNode.Content[0], certNode)
+			persist, err = nodeutils.MergeNodes(newCertNode.Content[0], certNode, nodeutils.WithPatchStrategyKey(KeyCerts), nodeutils.WithPatchStrategies(patchStrategies))
 			if err != nil {
 				return false, err
 			}
@@ -0,0 +1,222 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+// Proxy related environment variables honored by HTTPClientForContext.
+// Both the upper and lower case variants are accepted.
+const (
+	EnvHTTPProxy  = "HTTP_PROXY"
+	EnvHTTPSProxy = "HTTPS_PROXY"
+	EnvNoProxy    = "NO_PROXY"
+)
+
+// TLSConfigForHost returns a TLS configuration for the specified host built from
+// the cert configuration stored in the tanzu config.
+//
+// The returned configuration trusts the system cert pool combined with the CA
+// certificate configured for the host (if any). If the cert configuration for the
+// host sets SkipCertVerify (or Insecure) to true, certificate verification is
+// skipped and a warning is logged.
+// The host could be specified as hostname(or ipaddress) or host:port. If no cert
+// configuration matches host:port, the cert configuration for the hostname is used.
+func TLSConfigForHost(host string) (*tls.Config, error) {
+	if host == "" {
+		return nil, errors.New("host is empty")
+	}
+	certs, err := GetCerts()
+	if err != nil {
+		return nil, err
+	}
+	return tlsConfigFromCert(host, findCertForHost(certs, host))
+}
+
+// HTTPClientForContext returns an HTTP client configured to communicate with the
+// endpoint of the specified context.
+//
+// The TLS configuration of the client is built using TLSConfigForHost for the host of
+// the context endpoint. Proxies are determined from the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables. Values exported in the process environment take
+// precedence over the values configured as part of the tanzu configuration (see
+// GetEnvConfigurations).
+func HTTPClientForContext(ctxName string) (*http.Client, error) {
+	ctx, err := GetContext(ctxName)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := endpointFromContextIfExists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	host, err := hostFromEndpoint(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return httpClientForHost(host)
+}
+
+// httpClientForHost returns an HTTP client using the TLS configuration of the host and
+// the proxy configuration from the environment
+func httpClientForHost(host string) (*http.Client, error) {
+	tlsConfig, err := TLSConfigForHost(host)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = proxyFuncFromEnvConfigurations(GetEnvConfigurations())
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// findCertForHost returns the cert configuration matching the host. An exact match
+// of the host(host:port) takes precedence over a match of the hostname only.
+func findCertForHost(certs []*configtypes.Cert, host string) *configtypes.Cert {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	var hostnameMatch *configtypes.Cert
+	for _, cert := range certs {
+		if cert == nil {
+			continue
+		}
+		if cert.Host == host {
+			return cert
+		}
+		if cert.Host == hostname && hostnameMatch == nil {
+			hostnameMatch = cert
+		}
+	}
+	return hostnameMatch
+}
+
+// Pre-reqs: host != ""
+func tlsConfigFromCert(host string, cert *configtypes.Cert) (*tls.Config, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		MinVersion: tls.VersionTLS12,
+	}
+	if cert == nil {
+		return tlsConfig, nil
+	}
+
+	if cert.CACertData != "" {
+		caData, err := decodeCACertData(cert.CACertData)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid CA certificate data configured for host %q", host)
+		}
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, errors.Errorf("failed to parse CA certificate data configured for host %q", host)
+		}
+	}
+
+	if isTrueString(cert.SkipCertVerify) || isTrueString(cert.Insecure) {
+		log.Warningf("Skipping TLS certificate verification for host %q as per the cert configuration", host)
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // Explicitly configured by the user
+	}
+	return tlsConfig, nil
+}
+
+// decodeCACertData returns the PEM encoded CA data. The CA data is stored base64
+// encoded but PEM encoded data is accepted as well.
+func decodeCACertData(data string) ([]byte, error) {
+	trimmed := strings.TrimSpace(data)
+	if strings.HasPrefix(trimmed, "-----BEGIN") {
+		return []byte(trimmed), nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(trimmed)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func isTrueString(val string) bool {
+	b, err := strconv.ParseBool(strings.TrimSpace(val))
+	return err == nil && b
+}
+
+// endpointFromContextIfExists returns the endpoint of the context and an error
+// if the context does not have the options configured for its type
+func endpointFromContextIfExists(ctx *configtypes.Context) (string, error) {
+	switch ctx.ContextType {
+	case configtypes.ContextTypeK8s, configtypes.ContextTypeTanzu:
+		if ctx.ClusterOpts == nil || ctx.ClusterOpts.Endpoint == "" {
+			return "", errors.Errorf("context %q does not have an endpoint", ctx.Name)
+		}
+	case configtypes.ContextTypeTMC:
+		if ctx.GlobalOpts == nil || ctx.GlobalOpts.Endpoint == "" {
+			return "", errors.Errorf("context %q does not have an endpoint", ctx.Name)
+		}
+	}
+	return EndpointFromContext(ctx)
+}
+
+// hostFromEndpoint returns the host(host:port) of the endpoint. Endpoints without
+// a scheme are assumed to be https endpoints.
+func hostFromEndpoint(endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse endpoint %q", endpoint)
+	}
+	if u.Host == "" {
+		return "", errors.Errorf("failed to determine host from endpoint %q", endpoint)
+	}
+	return u.Host, nil
+}
+
+// proxyFuncFromEnvConfigurations returns the proxy function to be used by the HTTP
+// transport. Proxy variables exported in the process environment take precedence
+// over the ones specified in the env configurations.
+func proxyFuncFromEnvConfigurations(envs map[string]string) func(*http.Request) (*url.URL, error) {
+	lookup := func(key string) string {
+		for _, k := range []string{key, strings.ToLower(key)} {
+			if val, ok := os.LookupEnv(k); ok && val != "" {
+				return val
+			}
+		}
+		for _, k := range []string{key, strings.ToLower(key)} {
+			if val := envs[k]; val != "" {
+				return val
+			}
+		}
+		return ""
+	}
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  lookup(EnvHTTPProxy),
+		HTTPSProxy: lookup(EnvHTTPSProxy),
+		NoProxy:    lookup(EnvNoProxy),
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
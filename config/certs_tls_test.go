@@ -0,0 +1,224 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func encodedCACertData(server *httptest.Server) string {
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return base64.StdEncoding.EncodeToString(caPEM)
+}
+
+func TestTLSConfigForHost(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	host := serverURL.Host
+
+	tests := []struct {
+		name           string
+		cert           *configtypes.Cert
+		host           string
+		errStr         string
+		expectConnFail bool
+		skipVerify     bool
+	}{
+		{
+			name:           "should fail to connect when no cert is configured for the host",
+			host:           host,
+			expectConnFail: true,
+		},
+		{
+			name: "should connect when the CA of the host is configured",
+			cert: &configtypes.Cert{
+				Host:       host,
+				CACertData: encodedCACertData(server),
+			},
+			host: host,
+		},
+		{
+			name: "should use the cert configured for the hostname when host:port is not configured",
+			cert: &configtypes.Cert{
+				Host:       serverURL.Hostname(),
+				CACertData: encodedCACertData(server),
+			},
+			host: host,
+		},
+		{
+			name: "should accept PEM encoded CA data",
+			cert: &configtypes.Cert{
+				Host:       host,
+				CACertData: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+			},
+			host: host,
+		},
+		{
+			name: "should skip verification when SkipCertVerify is set",
+			cert: &configtypes.Cert{
+				Host:           host,
+				SkipCertVerify: "true",
+			},
+			host:       host,
+			skipVerify: true,
+		},
+		{
+			name: "should return error when the CA data is invalid",
+			cert: &configtypes.Cert{
+				Host:       host,
+				CACertData: base64.StdEncoding.EncodeToString([]byte("invalid")),
+			},
+			host:   host,
+			errStr: `failed to parse CA certificate data configured for host "` + host + `"`,
+		},
+		{
+			name:   "should return error when host is empty",
+			errStr: "host is empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, cleanUp := setupTestConfig(t, &CfgTestData{})
+			defer cleanUp()
+
+			if tc.cert != nil {
+				assert.NoError(t, SetCert(tc.cert))
+			}
+
+			tlsConfig, err := TLSConfigForHost(tc.host)
+			if tc.errStr != "" {
+				assert.EqualError(t, err, tc.errStr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.skipVerify, tlsConfig.InsecureSkipVerify)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+			resp, err := client.Get(server.URL)
+			if tc.expectConnFail {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestHTTPClientForContext(t *testing.T) {
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+	defer cleanUp()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	ctx := &configtypes.Context{
+		Name:        "test-ctx",
+		ContextType: configtypes.ContextTypeK8s,
+		ClusterOpts: &configtypes.ClusterServer{
+			Endpoint: server.URL,
+			Path:     "test-path",
+			Context:  "test-context",
+		},
+	}
+	assert.NoError(t, SetContext(ctx, false))
+
+	// Without the CA configured the client should not trust the server
+	client, err := HTTPClientForContext("test-ctx")
+	assert.NoError(t, err)
+	_, err = client.Get(server.URL)
+	assert.Error(t, err)
+
+	assert.NoError(t, SetCert(&configtypes.Cert{
+		Host:       serverURL.Host,
+		CACertData: encodedCACertData(server),
+	}))
+
+	client, err = HTTPClientForContext("test-ctx")
+	assert.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	_, err = HTTPClientForContext("non-existent")
+	assert.EqualError(t, err, "context non-existent not found")
+}
+
+func TestHTTPClientForContextProxy(t *testing.T) {
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+	defer cleanUp()
+
+	for _, key := range []string{EnvHTTPSProxy, "https_proxy", EnvNoProxy, "no_proxy"} {
+		if val, ok := os.LookupEnv(key); ok {
+			os.Unsetenv(key)
+			defer os.Setenv(key, val)
+		}
+	}
+
+	ctx := &configtypes.Context{
+		Name:        "test-ctx",
+		ContextType: configtypes.ContextTypeK8s,
+		ClusterOpts: &configtypes.ClusterServer{
+			Endpoint: "https://api.example.com:6443",
+			Path:     "test-path",
+			Context:  "test-context",
+		},
+	}
+	assert.NoError(t, SetContext(ctx, false))
+	assert.NoError(t, SetEnv(EnvHTTPSProxy, "http://proxy.example.com:3128"))
+	assert.NoError(t, SetEnv(EnvNoProxy, "internal.example.com"))
+
+	client, err := HTTPClientForContext("test-ctx")
+	assert.NoError(t, err)
+	transport, ok := client.Transport.(*http.Transport)
+	assert.True(t, ok)
+
+	req, err := http.NewRequest(http.MethodGet, "https://api.example.com:6443", http.NoBody)
+	assert.NoError(t, err)
+	proxyURL, err := transport.Proxy(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+
+	req, err = http.NewRequest(http.MethodGet, "https://internal.example.com", http.NoBody)
+	assert.NoError(t, err)
+	proxyURL, err = transport.Proxy(req)
+	assert.NoError(t, err)
+	assert.Nil(t, proxyURL)
+
+	// proxy exported in the process environment takes precedence over the config
+	os.Setenv(EnvHTTPSProxy, "http://env-proxy.example.com:3128")
+	defer os.Unsetenv(EnvHTTPSProxy)
+
+	client, err = HTTPClientForContext("test-ctx")
+	assert.NoError(t, err)
+	transport = client.Transport.(*http.Transport)
+	req, err = http.NewRequest(http.MethodGet, "https://api.example.com:6443", http.NoBody)
+	assert.NoError(t, err)
+	proxyURL, err = transport.Proxy(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "http://env-proxy.example.com:3128", proxyURL.String())
+}
@@ -5,6 +5,8 @@ package config
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
@@ -13,7 +15,9 @@ import (
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 )
 
-// GetCLIDiscoverySources retrieves cli discovery sources
+// GetCLIDiscoverySources retrieves the enabled cli discovery sources in the effective priority order.
+// Sources with higher priority are returned first and sources with the same priority retain their
+// configured order. Disabled sources are not returned, use GetAllCLIDiscoverySources to retrieve them.
 func GetCLIDiscoverySources() ([]configtypes.PluginDiscovery, error) {
 	// Retrieve client config node
 	node, err := getClientConfigNode()
@@ -24,6 +28,17 @@ func GetCLIDiscoverySources() ([]configtypes.PluginDiscovery, error) {
 	return getCLIDiscoverySources(node)
 }
 
+// GetAllCLIDiscoverySources retrieves all the cli discovery sources, including the disabled ones, in their configured order
+func GetAllCLIDiscoverySources() ([]configtypes.PluginDiscovery, error) {
+	// Retrieve client config node
+	node, err := getClientConfigNode()
+	if err != nil {
+		return nil, err
+	}
+
+	return getAllCLIDiscoverySources(node)
+}
+
 // GetCLIDiscoverySource retrieves cli discovery source by name assuming that there should only be one source with the name, returns the first match
 func GetCLIDiscoverySource(name string) (*configtypes.PluginDiscovery, error) {
 	// Retrieve client config node
@@ -106,7 +121,72 @@ func DeleteCLIDiscoverySource(name string) error {
 	return persistConfig(node)
 }
 
+// EnableCLIDiscoverySource enables the cli discoverySource by name
+func EnableCLIDiscoverySource(name string) error {
+	return updateCLIDiscoverySourceNode(name, func(discoverySourceNode *yaml.Node) {
+		setDiscoverySourceScalarField(discoverySourceNode, "disabled", "", "")
+	})
+}
+
+// DisableCLIDiscoverySource disables the cli discoverySource by name.
+// A disabled discovery source is retained in the config but is not used for plugin discovery.
+func DisableCLIDiscoverySource(name string) error {
+	return updateCLIDiscoverySourceNode(name, func(discoverySourceNode *yaml.Node) {
+		setDiscoverySourceScalarField(discoverySourceNode, "disabled", "true", "!!bool")
+	})
+}
+
+// SetCLIDiscoverySourcePriority sets the priority of the cli discoverySource by name.
+// Sources with higher priority take precedence when multiple sources provide the same plugin.
+func SetCLIDiscoverySourcePriority(name string, priority int) error {
+	return updateCLIDiscoverySourceNode(name, func(discoverySourceNode *yaml.Node) {
+		if priority == 0 {
+			setDiscoverySourceScalarField(discoverySourceNode, "priority", "", "")
+			return
+		}
+		setDiscoverySourceScalarField(discoverySourceNode, "priority", strconv.Itoa(priority), "!!int")
+	})
+}
+
+// ReorderCLIDiscoverySources reorders the cli discovery sources in the config as per the specified names.
+// The specified sources are moved to the front in the given order and the remaining sources
+// retain their relative order after them.
+// Note: The configured order determines the precedence among sources with the same priority.
+func ReorderCLIDiscoverySources(names []string) error {
+	// Retrieve client config node
+	AcquireTanzuConfigLock()
+	defer ReleaseTanzuConfigLock()
+	node, err := getClientConfigNodeNoLock()
+	if err != nil {
+		return err
+	}
+
+	// Reorder the cli discovery sources in the yaml node
+	err = reorderCLIDiscoverySources(node, names)
+	if err != nil {
+		return err
+	}
+
+	// Persist the config node to the file
+	return persistConfig(node)
+}
+
 func getCLIDiscoverySources(node *yaml.Node) ([]configtypes.PluginDiscovery, error) {
+	allDiscoverySources, err := getAllCLIDiscoverySources(node)
+	if err != nil {
+		return nil, err
+	}
+	discoverySources := make([]configtypes.PluginDiscovery, 0, len(allDiscoverySources))
+	for i := range allDiscoverySources {
+		if !allDiscoverySources[i].IsDisabled() {
+			discoverySources = append(discoverySources, allDiscoverySources[i])
+		}
+	}
+	sort.Stable(configtypes.DiscoverySorter(discoverySources))
+	return discoverySources, nil
+}
+
+func getAllCLIDiscoverySources(node *yaml.Node) ([]configtypes.PluginDiscovery, error) {
 	cfg, err := convertNodeToClientConfig(node)
 	if err != nil {
 		return nil, err
@@ -143,6 +223,12 @@ func getCLIDiscoverySource(node *yaml.Node, name string) (*configtypes.PluginDis
 
 // setCLIDiscoverySource Add/Update cli discovery source in the yaml node
 func setCLIDiscoverySource(node *yaml.Node, discoverySource configtypes.PluginDiscovery) (persist bool, err error) {
+	// Validate the structure of the discovery source
+	err = ValidateCLIDiscoverySource(discoverySource)
+	if err != nil {
+		return persist, err
+	}
+
 	// Retrieve the patch strategies from config metadata
 	patchStrategies, err := GetConfigMetadataPatchStrategy()
 	if err != nil {
@@ -189,7 +275,7 @@ func deleteCLIDiscoverySource(node *yaml.Node, name string) error {
 	var result []*yaml.Node
 	for _, discoverySourceNode := range cliDiscoverySourcesNode.Content {
 		// Find discovery source matched by discoverySourceType
-		if discoverySourceIndex := nodeutils.GetNodeIndex(discoverySourceNode.Content, discoverySourceType); discoverySourceIndex != -1 {
+		if discoverySourceIndex := nodeutils.GetNodeIndex(discoverySourceNode.Content, getDiscoverySourceTypeKey(discoverySourceType)); discoverySourceIndex != -1 {
 			// Find matching discovery source
 			if discoverySourceFieldIndex := nodeutils.GetNodeIndex(discoverySourceNode.Content[discoverySourceIndex].Content, "name"); discoverySourceFieldIndex != -1 && discoverySourceNode.Content[discoverySourceIndex].Content[discoverySourceFieldIndex].Value == discoverySourceName {
 				continue
@@ -201,3 +287,114 @@ func deleteCLIDiscoverySource(node *yaml.Node, name string) error {
 	cliDiscoverySourcesNode.Content = result
 	return nil
 }
+
+// updateCLIDiscoverySourceNode applies the update to the node of the cli discovery source specified by name
+// and persists the config
+func updateCLIDiscoverySourceNode(name string, update func(discoverySourceNode *yaml.Node)) error {
+	// Retrieve client config node
+	AcquireTanzuConfigLock()
+	defer ReleaseTanzuConfigLock()
+	node, err := getClientConfigNodeNoLock()
+	if err != nil {
+		return err
+	}
+
+	discoverySourceNode, err := findCLIDiscoverySourceNode(node, name)
+	if err != nil {
+		return err
+	}
+	update(discoverySourceNode)
+
+	// Persist the config node to the file
+	return persistConfig(node)
+}
+
+// findCLIDiscoverySourceNode returns the node of the discovery type of the cli discovery source specified by name
+func findCLIDiscoverySourceNode(node *yaml.Node, name string) (*yaml.Node, error) {
+	if name == "" {
+		return nil, errors.New("discovery source name cannot be empty")
+	}
+	keys := []nodeutils.Key{
+		{Name: KeyCLI},
+		{Name: KeyDiscoverySources},
+	}
+	cliDiscoverySourcesNode := nodeutils.FindNode(node.Content[0], nodeutils.WithKeys(keys))
+	if cliDiscoverySourcesNode == nil {
+		return nil, errors.New("cli discovery source not found")
+	}
+	discoverySourceNode := findCLIDiscoverySourceEntryNode(cliDiscoverySourcesNode, name)
+	if discoverySourceNode == nil {
+		return nil, errors.New("cli discovery source not found")
+	}
+	discoverySourceType, discoverySourceIndex := findDiscoverySourceTypeAndIndexByWeakMatch(discoverySourceNode.Content)
+	if discoverySourceType == DiscoveryTypeGCP {
+		return nil, errors.Errorf("discovery source %q of type %q does not support priority and disabled state", name, DiscoveryTypeGCP)
+	}
+	return discoverySourceNode.Content[discoverySourceIndex], nil
+}
+
+// setDiscoverySourceScalarField sets the scalar field of the discovery source node. The field is removed
+// if the value is empty
+func setDiscoverySourceScalarField(discoverySourceNode *yaml.Node, key, value, tag string) {
+	var result []*yaml.Node
+	for i := 0; i+1 < len(discoverySourceNode.Content); i += 2 {
+		if discoverySourceNode.Content[i].Value == key {
+			continue
+		}
+		result = append(result, discoverySourceNode.Content[i], discoverySourceNode.Content[i+1])
+	}
+	if value != "" {
+		fieldNodes := nodeutils.CreateScalarNode(key, value)
+		fieldNodes[1].Tag = tag
+		result = append(result, fieldNodes...)
+	}
+	discoverySourceNode.Content = result
+}
+
+func reorderCLIDiscoverySources(node *yaml.Node, names []string) error {
+	keys := []nodeutils.Key{
+		{Name: KeyCLI},
+		{Name: KeyDiscoverySources},
+	}
+	cliDiscoverySourcesNode := nodeutils.FindNode(node.Content[0], nodeutils.WithKeys(keys))
+	if cliDiscoverySourcesNode == nil {
+		return errors.New("cli discovery sources not found")
+	}
+
+	moved := make(map[*yaml.Node]bool)
+	var result []*yaml.Node
+	for _, name := range names {
+		discoverySourceNode := findCLIDiscoverySourceEntryNode(cliDiscoverySourcesNode, name)
+		if discoverySourceNode == nil {
+			return errors.Errorf("cli discovery source %q not found", name)
+		}
+		if moved[discoverySourceNode] {
+			return errors.Errorf("cli discovery source %q is specified more than once", name)
+		}
+		moved[discoverySourceNode] = true
+		result = append(result, discoverySourceNode)
+	}
+	for _, discoverySourceNode := range cliDiscoverySourcesNode.Content {
+		if !moved[discoverySourceNode] {
+			result = append(result, discoverySourceNode)
+		}
+	}
+	cliDiscoverySourcesNode.Style = 0
+	cliDiscoverySourcesNode.Content = result
+	return nil
+}
+
+// findCLIDiscoverySourceEntryNode returns the entry of the cli discovery sources node matching the name
+func findCLIDiscoverySourceEntryNode(cliDiscoverySourcesNode *yaml.Node, name string) *yaml.Node {
+	for _, discoverySourceNode := range cliDiscoverySourcesNode.Content {
+		_, discoverySourceIndex := findDiscoverySourceTypeAndIndexByWeakMatch(discoverySourceNode.Content)
+		if discoverySourceIndex == -1 {
+			continue
+		}
+		discoverySourceTypeNode := discoverySourceNode.Content[discoverySourceIndex]
+		if nameIdx := nodeutils.GetNodeIndex(discoverySourceTypeNode.Content, "name"); nameIdx != -1 && discoverySourceTypeNode.Content[nameIdx].Value == name {
+			return discoverySourceNode
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/internal/kubeconfig"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// DefaultDiscoverySourceProbeTimeout is the default time to wait for a discovery source to respond
+const DefaultDiscoverySourceProbeTimeout = 10 * time.Second
+
+// localDiscoveryDirName is the directory, relative to the home directory, in which the CLI looks up the
+// relative paths of local discovery sources
+const localDiscoveryDirName = ".config/tanzu-plugins/discovery"
+
+// ociManifestMediaTypes are the media types accepted when probing an OCI image manifest
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+// ProbeDiscoverySource checks the reachability of the cli discovery source specified by name.
+//
+//   - OCI: the registry API is reachable and the image manifest exists (when the registry allows anonymous access).
+//     The manifest is looked up by the pinned digest, if any
+//   - Local: the path is an accessible directory. A relative path is resolved the way the CLI does, i.e. relative
+//     to the local discovery directory ~/.config/tanzu-plugins/discovery
+//   - Kubernetes: the API server of the kubeconfig context accepts connections. If neither Path nor KubeConfigBytes
+//     is specified, the kubeconfig is loaded with the default loading rules i.e. KUBECONFIG or ~/.kube/config
+//   - REST: the endpoint responds to HTTP requests
+//
+// The cert configuration stored for the registry or endpoint host is used to communicate over TLS.
+func ProbeDiscoverySource(name string) error {
+	discoverySource, err := GetCLIDiscoverySource(name)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDiscoverySourceProbeTimeout)
+	defer cancel()
+
+	switch {
+	case discoverySource.OCI != nil:
+		err = probeOCIDiscovery(ctx, discoverySource.OCI)
+	case discoverySource.Local != nil:
+		err = probeLocalDiscovery(discoverySource.Local)
+	case discoverySource.Kubernetes != nil:
+		err = probeKubernetesDiscovery(ctx, discoverySource.Kubernetes)
+	case discoverySource.REST != nil:
+		err = probeRESTDiscovery(ctx, discoverySource.REST)
+	default:
+		return errors.Errorf("probing discovery source %q is not supported for its discovery type", name)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "discovery source %q is not reachable", name)
+	}
+	return nil
+}
+
+func probeOCIDiscovery(ctx context.Context, ociDiscovery *configtypes.OCIDiscovery) error {
+	ref, err := ResolveOCIDiscoveryImage(ociDiscovery)
+	if err != nil {
+		return err
+	}
+	// The registry API of the default registry is served by its index
+	registry := ref.Registry
+	if registry == DefaultImageRegistry {
+		registry = "index.docker.io"
+	}
+	reference := ref.Digest
+	if reference == "" {
+		reference = ref.Tag
+	}
+
+	client, err := httpClientForHost(registry)
+	if err != nil {
+		return err
+	}
+
+	// Ping the registry API. Registries requiring authentication respond with 401
+	status, err := doProbeRequest(ctx, client, http.MethodGet, fmt.Sprintf("https://%s/v2/", registry), nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusUnauthorized {
+		return errors.Errorf("registry %q responded with unexpected status %d", registry, status)
+	}
+	if status == http.StatusUnauthorized {
+		return nil
+	}
+
+	headers := map[string]string{"Accept": strings.Join(ociManifestMediaTypes, ",")}
+	status, err = doProbeRequest(ctx, client, http.MethodHead, fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, ref.Repository, reference), headers)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK, http.StatusUnauthorized, http.StatusForbidden:
+		return nil
+	case http.StatusNotFound:
+		return errors.Errorf("image %q not found", ociDiscovery.Image)
+	default:
+		return errors.Errorf("registry %q responded with unexpected status %d for image %q", registry, status, ociDiscovery.Image)
+	}
+}
+
+func probeLocalDiscovery(localDiscovery *configtypes.LocalDiscovery) error {
+	path := localDiscovery.Path
+	if !filepath.IsAbs(path) {
+		var err error
+		path, err = localDirPath(filepath.Join(localDiscoveryDirName, path))
+		if err != nil {
+			return err
+		}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrapf(err, "local discovery path %q is not accessible", path)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("local discovery path %q is not a directory", path)
+	}
+	return nil
+}
+
+func probeKubernetesDiscovery(ctx context.Context, k8sDiscovery *configtypes.KubernetesDiscovery) error {
+	var kc *kubeconfig.Config
+	switch {
+	case k8sDiscovery.Path != "":
+		var err error
+		kc, err = kubeconfig.ReadKubeConfig(k8sDiscovery.Path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the kubeconfig %q", k8sDiscovery.Path)
+		}
+	case len(k8sDiscovery.KubeConfigBytes) != 0:
+		kc = &kubeconfig.Config{}
+		if err := yaml.Unmarshal(k8sDiscovery.KubeConfigBytes, kc); err != nil {
+			return errors.Wrap(err, "failed to parse the kubeconfig bytes")
+		}
+	default:
+		var err error
+		kc, err = kubeconfig.ReadDefaultKubeConfig()
+		if err != nil {
+			return err
+		}
+	}
+
+	kubeContextName := k8sDiscovery.Context
+	if kubeContextName == "" {
+		kubeContextName = kc.CurrentContext
+	}
+	if kubeContextName == "" {
+		return errors.New("kubeconfig does not have a current context and no context is specified")
+	}
+	kubeContext := kubeconfig.GetContext(kc, kubeContextName)
+	if kubeContext == nil {
+		return errors.Errorf("context %q missing in the kubeconfig", kubeContextName)
+	}
+	cluster := kubeconfig.GetCluster(kc, kubeContext.Context.Cluster)
+	if cluster == nil {
+		return errors.Errorf("cluster %q missing in the kubeconfig", kubeContext.Context.Cluster)
+	}
+
+	u, err := url.Parse(cluster.Cluster.Server)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse the server %q", cluster.Cluster.Server)
+	}
+	address := u.Host
+	if u.Port() == "" {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to the server %q", cluster.Cluster.Server)
+	}
+	return conn.Close()
+}
+
+func probeRESTDiscovery(ctx context.Context, restDiscovery *configtypes.GenericRESTDiscovery) error {
+	if restDiscovery.Endpoint == "" {
+		return errors.New("endpoint is not specified")
+	}
+	endpoint := restDiscovery.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpoint = strings.TrimRight(endpoint, "/") + "/" + strings.TrimLeft(restDiscovery.BasePath, "/")
+
+	host, err := hostFromEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+	client, err := httpClientForHost(host)
+	if err != nil {
+		return err
+	}
+	status, err := doProbeRequest(ctx, client, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	if status >= http.StatusInternalServerError {
+		return errors.Errorf("endpoint %q responded with status %d", endpoint, status)
+	}
+	return nil
+}
+
+// doProbeRequest sends the request and returns the response status code
+func doProbeRequest(ctx context.Context, client *http.Client, method, reqURL string, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// fakeRegistryDigest is the digest of the image manifest served by the fake registry
+var fakeRegistryDigest = "sha256:" + strings.Repeat("ab", 32)
+
+// newFakeRegistry returns a TLS server implementing the subset of the OCI distribution API
+// used when probing OCI discovery sources. Only the manifests of the image "tanzu/plugins:v1" and
+// the digest fakeRegistryDigest exist
+func newFakeRegistry() *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/":
+			w.WriteHeader(http.StatusOK)
+		case "/v2/tanzu/plugins/manifests/v1", "/v2/tanzu/plugins/manifests/" + fakeRegistryDigest:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	return server
+}
+
+func TestProbeDiscoverySource(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	registry := newFakeRegistry()
+	defer registry.Close()
+	registryURL, err := url.Parse(registry.URL)
+	assert.NoError(t, err)
+
+	err = SetCert(&configtypes.Cert{Host: registryURL.Host, CACertData: encodedCACertData(registry)})
+	assert.NoError(t, err)
+
+	restServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer restServer.Close()
+
+	localDir := setupLocalDiscoveryPaths(t, "local-dir")
+	err = os.WriteFile(filepath.Join(localDir, "local-file"), []byte("file"), 0644)
+	assert.NoError(t, err)
+
+	// Relative local paths are resolved under the local discovery directory of the home directory
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	err = os.MkdirAll(filepath.Join(homeDir, localDiscoveryDirName, "standalone"), 0755)
+	assert.NoError(t, err)
+
+	sources := []configtypes.PluginDiscovery{
+		{OCI: &configtypes.OCIDiscovery{Name: "oci-found", Image: fmt.Sprintf("%s/tanzu/plugins:v1", registryURL.Host)}},
+		{OCI: &configtypes.OCIDiscovery{Name: "oci-not-found", Image: fmt.Sprintf("%s/tanzu/plugins:v2", registryURL.Host)}},
+		{OCI: &configtypes.OCIDiscovery{Name: "oci-pinned", Image: fmt.Sprintf("%s/tanzu/plugins:v2", registryURL.Host), Digest: fakeRegistryDigest}},
+		{OCI: &configtypes.OCIDiscovery{Name: "oci-pinned-not-found", Image: fmt.Sprintf("%s/tanzu/plugins:v1", registryURL.Host), Digest: "sha256:" + strings.Repeat("cd", 32)}},
+		{OCI: &configtypes.OCIDiscovery{Name: "oci-malformed", Image: fmt.Sprintf("%s/Tanzu/plugins:v1", registryURL.Host)}},
+		{Local: &configtypes.LocalDiscovery{Name: "local", Path: localDir}},
+		{Local: &configtypes.LocalDiscovery{Name: "local-relative", Path: "standalone"}},
+		{Local: &configtypes.LocalDiscovery{Name: "local-missing", Path: filepath.Join(localDir, "missing")}},
+		{Local: &configtypes.LocalDiscovery{Name: "local-file", Path: filepath.Join(localDir, "local-file")}},
+		{REST: &configtypes.GenericRESTDiscovery{Name: "rest", Endpoint: restServer.URL, BasePath: "plugins"}},
+		{REST: &configtypes.GenericRESTDiscovery{Name: "rest-broken", Endpoint: restServer.URL, BasePath: "broken"}},
+	}
+	err = SetCLIDiscoverySources(sources)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		errStr string
+	}{
+		{
+			name: "oci-found",
+		},
+		{
+			name:   "oci-not-found",
+			errStr: fmt.Sprintf("discovery source \"oci-not-found\" is not reachable: image \"%s/tanzu/plugins:v2\" not found", registryURL.Host),
+		},
+		{
+			name: "oci-pinned",
+		},
+		{
+			name:   "oci-pinned-not-found",
+			errStr: "discovery source \"oci-pinned-not-found\" is not reachable: image",
+		},
+		{
+			name:   "oci-malformed",
+			errStr: "invalid repository path component \"Tanzu\"",
+		},
+		{
+			name: "local",
+		},
+		{
+			name: "local-relative",
+		},
+		{
+			name:   "local-missing",
+			errStr: "is not accessible",
+		},
+		{
+			name:   "local-file",
+			errStr: "is not a directory",
+		},
+		{
+			name: "rest",
+		},
+		{
+			name:   "rest-broken",
+			errStr: "discovery source \"rest-broken\" is not reachable: endpoint",
+		},
+		{
+			name:   "missing",
+			errStr: "cli discovery source not found",
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			err := ProbeDiscoverySource(spec.name)
+			if spec.errStr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, spec.errStr)
+			}
+		})
+	}
+}
+
+func TestProbeDiscoverySourceWithDefaultKubeconfig(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	kubeconfigDir := t.TempDir()
+	kubeconfigPath := filepath.Join(kubeconfigDir, "config")
+	kubeconfigData := fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test-context
+clusters:
+  - cluster:
+      server: %s
+    name: test-cluster
+contexts:
+  - context:
+      cluster: test-cluster
+      user: test-user
+    name: test-context
+users:
+  - name: test-user
+`, server.URL)
+	err := os.WriteFile(kubeconfigPath, []byte(kubeconfigData), 0600)
+	assert.NoError(t, err)
+
+	err = SetCLIDiscoverySource(configtypes.PluginDiscovery{Kubernetes: &configtypes.KubernetesDiscovery{Name: "k8s"}})
+	assert.NoError(t, err)
+
+	// The kubeconfig files listed in KUBECONFIG are used, missing ones are ignored
+	t.Setenv("KUBECONFIG", strings.Join([]string{filepath.Join(kubeconfigDir, "missing"), kubeconfigPath}, string(os.PathListSeparator)))
+	err = ProbeDiscoverySource("k8s")
+	assert.NoError(t, err)
+
+	// The kubeconfig of the home directory is used when KUBECONFIG is not set
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("KUBECONFIG", "")
+	err = ProbeDiscoverySource("k8s")
+	assert.ErrorContains(t, err, "discovery source \"k8s\" is not reachable: kubeconfig does not have a current context")
+
+	err = os.MkdirAll(filepath.Join(homeDir, ".kube"), 0755)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(homeDir, ".kube", "config"), []byte(kubeconfigData), 0600)
+	assert.NoError(t, err)
+	err = ProbeDiscoverySource("k8s")
+	assert.NoError(t, err)
+}
@@ -693,3 +693,126 @@ func TestSetCLIDiscoverySourceMultiTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestCLIDiscoverySourcesPriorityOrder(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	err := SetCLIDiscoverySources([]configtypes.PluginDiscovery{
+		{OCI: &configtypes.OCIDiscovery{Name: "oci", Image: "default-image"}},
+		{Local: &configtypes.LocalDiscovery{Name: "local", Path: "local-path"}},
+		{REST: &configtypes.GenericRESTDiscovery{Name: "rest", Endpoint: "https://example.com", Priority: 10}},
+		{Kubernetes: &configtypes.KubernetesDiscovery{Name: "k8s"}},
+	})
+	assert.NoError(t, err)
+
+	getNames := func() []string {
+		sources, err := GetCLIDiscoverySources()
+		assert.NoError(t, err)
+		var names []string
+		for _, source := range sources {
+			_, name, err := getDiscoverySourceTypeAndName(source)
+			assert.NoError(t, err)
+			names = append(names, name)
+		}
+		return names
+	}
+
+	// Sources with higher priority come first, others retain the configured order
+	assert.Equal(t, []string{"rest", "oci", "local", "k8s"}, getNames())
+
+	// Reorder the sources
+	err = ReorderCLIDiscoverySources([]string{"k8s", "local"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rest", "k8s", "local", "oci"}, getNames())
+
+	err = ReorderCLIDiscoverySources([]string{"k8s", "missing"})
+	assert.ErrorContains(t, err, "cli discovery source \"missing\" not found")
+	err = ReorderCLIDiscoverySources([]string{"k8s", "k8s"})
+	assert.ErrorContains(t, err, "cli discovery source \"k8s\" is specified more than once")
+
+	// Update the priorities
+	err = SetCLIDiscoverySourcePriority("oci", 20)
+	assert.NoError(t, err)
+	err = SetCLIDiscoverySourcePriority("rest", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"oci", "k8s", "local", "rest"}, getNames())
+
+	source, err := GetCLIDiscoverySource("oci")
+	assert.NoError(t, err)
+	assert.Equal(t, 20, source.OCI.Priority)
+	source, err = GetCLIDiscoverySource("rest")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, source.REST.Priority)
+
+	// Disabled sources are not used, but are retained in the config
+	err = DisableCLIDiscoverySource("oci")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"k8s", "local", "rest"}, getNames())
+	source, err = GetCLIDiscoverySource("oci")
+	assert.NoError(t, err)
+	assert.True(t, source.OCI.Disabled)
+
+	allSources, err := GetAllCLIDiscoverySources()
+	assert.NoError(t, err)
+	assert.Len(t, allSources, 4)
+	assert.NotNil(t, allSources[2].OCI)
+	assert.True(t, allSources[2].OCI.Disabled)
+
+	err = EnableCLIDiscoverySource("oci")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"oci", "k8s", "local", "rest"}, getNames())
+	source, err = GetCLIDiscoverySource("oci")
+	assert.NoError(t, err)
+	assert.False(t, source.OCI.Disabled)
+
+	err = DisableCLIDiscoverySource("missing")
+	assert.ErrorContains(t, err, "cli discovery source not found")
+}
+
+func TestSetCLIDiscoverySourceWithDigestAndVerification(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	discoverySource := configtypes.PluginDiscovery{
+		OCI: &configtypes.OCIDiscovery{
+			Name:   "default",
+			Image:  "localhost:5000/tanzu/plugins:latest",
+			Digest: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			Verification: &configtypes.OCIImageVerification{
+				Keyless: &configtypes.OCIKeylessVerification{
+					Issuer:   "https://token.actions.githubusercontent.com",
+					Identity: "https://github.com/vmware-tanzu/tanzu-cli/.github/workflows/release.yaml@refs/heads/main",
+				},
+			},
+		},
+	}
+	err := SetCLIDiscoverySource(discoverySource)
+	assert.NoError(t, err)
+
+	source, err := GetCLIDiscoverySource("default")
+	assert.NoError(t, err)
+	assert.Equal(t, discoverySource, *source)
+
+	ref, err := ResolveOCIDiscoveryImage(source.OCI)
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:5000/tanzu/plugins:latest@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", ref.String())
+
+	// Pinning a digest that does not match the digest of the image is rejected
+	err = SetCLIDiscoverySource(configtypes.PluginDiscovery{
+		OCI: &configtypes.OCIDiscovery{
+			Name:   "default",
+			Image:  "localhost:5000/tanzu/plugins:latest@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			Digest: "sha256:fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		},
+	})
+	assert.ErrorContains(t, err, "does not match the digest")
+}
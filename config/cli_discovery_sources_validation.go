@@ -0,0 +1,155 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/internal/kubeconfig"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// ValidateCLIDiscoverySource validates the structure of the discovery source.
+//
+// A discovery source is valid when exactly one discovery type is set and the name is not empty.
+// Additionally,
+//   - OCI discovery source must specify an image. If a digest is pinned, it must be well-formed and
+//     match the digest in the image reference (if any). If verification is configured, exactly one
+//     of public key or keyless identity must be specified
+//   - Local discovery source must specify a path
+//   - Kubernetes discovery source must not specify both Path and KubeConfigBytes, and the
+//     specified kubeconfig must contain the specified context (or a current context)
+//
+// The image reference and the existence of the local path are checked by ProbeDiscoverySource.
+func ValidateCLIDiscoverySource(discoverySource configtypes.PluginDiscovery) error {
+	count := 0
+	//nolint:staticcheck // Deprecated
+	for _, isSet := range []bool{discoverySource.GCP != nil, discoverySource.OCI != nil, discoverySource.Local != nil,
+		discoverySource.Kubernetes != nil, discoverySource.REST != nil} {
+		if isSet {
+			count++
+		}
+	}
+	if count == 0 {
+		return errors.New("discovery source type cannot be empty")
+	}
+	if count > 1 {
+		return errors.New("only one discovery source type can be specified")
+	}
+
+	_, name, err := getDiscoverySourceTypeAndName(discoverySource)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case discoverySource.OCI != nil:
+		if err := validateOCIDiscovery(discoverySource.OCI); err != nil {
+			return errors.Wrapf(err, "invalid discovery source %q", name)
+		}
+	case discoverySource.Local != nil:
+		if discoverySource.Local.Path == "" {
+			return errors.Errorf("invalid discovery source %q: local discovery path cannot be empty", name)
+		}
+	case discoverySource.Kubernetes != nil:
+		if err := validateKubernetesDiscovery(discoverySource.Kubernetes); err != nil {
+			return errors.Wrapf(err, "invalid discovery source %q", name)
+		}
+	}
+	return nil
+}
+
+// ResolveOCIDiscoveryImage returns the normalized image reference of the OCI discovery source.
+// If a digest is pinned for the discovery source, the returned reference is pinned to the digest.
+func ResolveOCIDiscoveryImage(ociDiscovery *configtypes.OCIDiscovery) (*ImageReference, error) {
+	if ociDiscovery == nil {
+		return nil, errors.New("oci discovery cannot be empty")
+	}
+	ref, err := ParseImageReference(ociDiscovery.Image)
+	if err != nil {
+		return nil, err
+	}
+	if ociDiscovery.Digest == "" {
+		return ref, nil
+	}
+	digest, err := normalizeImageDigest(ociDiscovery.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Digest != "" && ref.Digest != digest {
+		return nil, errors.Errorf("digest %q does not match the digest %q of the image %q", ociDiscovery.Digest, ref.Digest, ociDiscovery.Image)
+	}
+	ref.Digest = digest
+	return ref, nil
+}
+
+func validateOCIDiscovery(ociDiscovery *configtypes.OCIDiscovery) error {
+	if ociDiscovery.Image == "" {
+		return errors.New("image reference cannot be empty")
+	}
+	if ociDiscovery.Digest != "" {
+		digest, err := normalizeImageDigest(ociDiscovery.Digest)
+		if err != nil {
+			return err
+		}
+		if _, imageDigest, found := strings.Cut(ociDiscovery.Image, "@"); found {
+			if normalized, err := normalizeImageDigest(imageDigest); err == nil && normalized != digest {
+				return errors.Errorf("digest %q does not match the digest %q of the image %q", ociDiscovery.Digest, normalized, ociDiscovery.Image)
+			}
+		}
+	}
+	verification := ociDiscovery.Verification
+	if verification == nil {
+		return nil
+	}
+	if verification.PublicKey != "" && verification.Keyless != nil {
+		return errors.New("only one of public key or keyless verification can be specified")
+	}
+	if verification.PublicKey == "" && verification.Keyless == nil {
+		return errors.New("either public key or keyless verification must be specified")
+	}
+	if verification.Keyless != nil && (verification.Keyless.Issuer == "" || verification.Keyless.Identity == "") {
+		return errors.New("keyless verification must specify both issuer and identity")
+	}
+	return nil
+}
+
+func validateKubernetesDiscovery(k8sDiscovery *configtypes.KubernetesDiscovery) error {
+	if k8sDiscovery.Path != "" && len(k8sDiscovery.KubeConfigBytes) != 0 {
+		return errors.New("only one of kubeconfig path or kubeconfig bytes can be specified")
+	}
+
+	var kc *kubeconfig.Config
+	switch {
+	case k8sDiscovery.Path != "":
+		var err error
+		kc, err = kubeconfig.ReadKubeConfig(k8sDiscovery.Path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read the kubeconfig %q", k8sDiscovery.Path)
+		}
+	case len(k8sDiscovery.KubeConfigBytes) != 0:
+		kc = &kubeconfig.Config{}
+		if err := yaml.Unmarshal(k8sDiscovery.KubeConfigBytes, kc); err != nil {
+			return errors.Wrap(err, "failed to parse the kubeconfig bytes")
+		}
+	default:
+		// Neither is specified, the default kubeconfig would be used
+		return nil
+	}
+
+	kubeContext := k8sDiscovery.Context
+	if kubeContext == "" {
+		kubeContext = kc.CurrentContext
+	}
+	if kubeContext == "" {
+		return errors.New("kubeconfig does not have a current context and no context is specified")
+	}
+	if kubeconfig.GetContext(kc, kubeContext) == nil {
+		return errors.Errorf("context %q missing in the kubeconfig", kubeContext)
+	}
+	return nil
+}
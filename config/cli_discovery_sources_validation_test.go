@@ -0,0 +1,212 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestValidateCLIDiscoverySource(t *testing.T) {
+	localDir := setupLocalDiscoveryPaths(t, "local-dir")
+
+	sha256Digest := "sha256:" + strings.Repeat("ab", 32)
+
+	kubeconfigPath := "../fakes/config/kubeconfig-1.yaml"
+	kubeconfigBytes, err := os.ReadFile(kubeconfigPath)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		in     configtypes.PluginDiscovery
+		errStr string
+	}{
+		{
+			name:   "should return error when no discovery type is set",
+			in:     configtypes.PluginDiscovery{},
+			errStr: "discovery source type cannot be empty",
+		},
+		{
+			name: "should return error when multiple discovery types are set",
+			in: configtypes.PluginDiscovery{
+				OCI:   &configtypes.OCIDiscovery{Name: "test", Image: "test-image:v1"},
+				Local: &configtypes.LocalDiscovery{Name: "test", Path: filepath.Join(localDir, "local-dir")},
+			},
+			errStr: "only one discovery source type can be specified",
+		},
+		{
+			name: "should return error when name is empty",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Image: "test-image:v1"},
+			},
+			errStr: "discovery source name cannot be empty",
+		},
+		{
+			name: "success oci discovery with registry, tag and digest",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/tanzu/plugins/central:v1@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+			},
+		},
+		{
+			name: "success oci discovery with image reference checked by the probe",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "Test-Image:v1"},
+			},
+		},
+		{
+			name: "should return error when oci image is empty",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test"},
+			},
+			errStr: "invalid discovery source \"test\": image reference cannot be empty",
+		},
+		{
+			name: "success oci discovery with pinned digest",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1@" + sha256Digest, Digest: "sha256:" + strings.Repeat("AB", 32)},
+			},
+		},
+		{
+			name: "should return error when pinned digest does not match the image digest",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1@" + sha256Digest, Digest: "sha256:" + strings.Repeat("cd", 32)},
+			},
+			errStr: "does not match the digest \"" + sha256Digest + "\" of the image",
+		},
+		{
+			name: "should return error when pinned digest is malformed",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1", Digest: "sha256:abc"},
+			},
+			errStr: "invalid discovery source \"test\": invalid digest \"sha256:abc\"",
+		},
+		{
+			name: "success oci discovery with public key verification",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1", Verification: &configtypes.OCIImageVerification{PublicKey: "/path/to/cosign.pub"}},
+			},
+		},
+		{
+			name: "success oci discovery with keyless verification",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1", Verification: &configtypes.OCIImageVerification{
+					Keyless: &configtypes.OCIKeylessVerification{Issuer: "https://issuer.example.com", Identity: "signer@example.com"}}},
+			},
+		},
+		{
+			name: "should return error when both public key and keyless verification are set",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1", Verification: &configtypes.OCIImageVerification{PublicKey: "/path/to/cosign.pub",
+					Keyless: &configtypes.OCIKeylessVerification{Issuer: "https://issuer.example.com", Identity: "signer@example.com"}}},
+			},
+			errStr: "only one of public key or keyless verification can be specified",
+		},
+		{
+			name: "should return error when verification is empty",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1", Verification: &configtypes.OCIImageVerification{}},
+			},
+			errStr: "either public key or keyless verification must be specified",
+		},
+		{
+			name: "should return error when keyless verification does not specify identity",
+			in: configtypes.PluginDiscovery{
+				OCI: &configtypes.OCIDiscovery{Name: "test", Image: "localhost:5000/plugins:v1", Verification: &configtypes.OCIImageVerification{
+					Keyless: &configtypes.OCIKeylessVerification{Issuer: "https://issuer.example.com"}}},
+			},
+			errStr: "keyless verification must specify both issuer and identity",
+		},
+		{
+			name: "success local discovery",
+			in: configtypes.PluginDiscovery{
+				Local: &configtypes.LocalDiscovery{Name: "test", Path: filepath.Join(localDir, "local-dir")},
+			},
+		},
+		{
+			name: "success local discovery with relative path",
+			in: configtypes.PluginDiscovery{
+				Local: &configtypes.LocalDiscovery{Name: "test", Path: "standalone"},
+			},
+		},
+		{
+			name: "should return error when local path is empty",
+			in: configtypes.PluginDiscovery{
+				Local: &configtypes.LocalDiscovery{Name: "test"},
+			},
+			errStr: "invalid discovery source \"test\": local discovery path cannot be empty",
+		},
+		{
+			name: "success kubernetes discovery with default kubeconfig",
+			in: configtypes.PluginDiscovery{
+				Kubernetes: &configtypes.KubernetesDiscovery{Name: "test"},
+			},
+		},
+		{
+			name: "success kubernetes discovery with kubeconfig path and current context",
+			in: configtypes.PluginDiscovery{
+				Kubernetes: &configtypes.KubernetesDiscovery{Name: "test", Path: kubeconfigPath},
+			},
+		},
+		{
+			name: "success kubernetes discovery with kubeconfig bytes and context",
+			in: configtypes.PluginDiscovery{
+				Kubernetes: &configtypes.KubernetesDiscovery{Name: "test", KubeConfigBytes: kubeconfigBytes, Context: "bar-context"},
+			},
+		},
+		{
+			name: "should return error when both kubeconfig path and bytes are set",
+			in: configtypes.PluginDiscovery{
+				Kubernetes: &configtypes.KubernetesDiscovery{Name: "test", Path: kubeconfigPath, KubeConfigBytes: kubeconfigBytes},
+			},
+			errStr: "invalid discovery source \"test\": only one of kubeconfig path or kubeconfig bytes can be specified",
+		},
+		{
+			name: "should return error when context is missing in the kubeconfig",
+			in: configtypes.PluginDiscovery{
+				Kubernetes: &configtypes.KubernetesDiscovery{Name: "test", Path: kubeconfigPath, Context: "missing-context"},
+			},
+			errStr: "invalid discovery source \"test\": context \"missing-context\" missing in the kubeconfig",
+		},
+		{
+			name: "success rest discovery",
+			in: configtypes.PluginDiscovery{
+				REST: &configtypes.GenericRESTDiscovery{Name: "test", Endpoint: "https://example.com"},
+			},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			err := ValidateCLIDiscoverySource(spec.in)
+			if spec.errStr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, spec.errStr)
+			}
+		})
+	}
+}
+
+func TestSetCLIDiscoverySourceValidation(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	err := SetCLIDiscoverySource(configtypes.PluginDiscovery{
+		OCI:   &configtypes.OCIDiscovery{Name: "test", Image: "test-image:v1"},
+		Local: &configtypes.LocalDiscovery{Name: "test", Path: "standalone"},
+	})
+	assert.ErrorContains(t, err, "only one discovery source type can be specified")
+
+	_, err = GetCLIDiscoverySource("test")
+	assert.ErrorContains(t, err, "cli discovery source not found")
+}
@@ -106,7 +106,7 @@ func setCLITelemetryOptions(node *yaml.Node, telemetryOptions *configtypes.Telem
 	if err != nil {
 		return false, err
 	}
-	persist, err = nodeutils.MergeNodes(newTelemetryNode.Content[0], telemetryOptionsNode)
+	persist, err = nodeutils.MergeNodes(newTelemetryNode.Content[0], telemetryOptionsNode, nodeutils.WithPatchStrategyKey(KeyTelemetry), nodeutils.WithPatchStrategies(patchStrategies))
 	if err != nil {
 		return false, err
 	}
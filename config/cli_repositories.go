@@ -218,7 +218,7 @@ func setRepository(repositoriesNode *yaml.Node, repository configtypes.PluginRep
 					return false, err
 				}
 				// merge the new node into repository node
-				persist, err = nodeutils.MergeNodes(newNode.Content[0], repositoryNode)
+				persist, err = nodeutils.MergeNodes(newNode.Content[0], repositoryNode, patchStrategyOpts...)
 				if err != nil {
 					return false, err
 				}
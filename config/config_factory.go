@@ -195,9 +195,17 @@ func persistNode(node *yaml.Node, opts ...CfgOpts) error {
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal nodeutils")
 	}
+	snapshotLimit := getConfigSnapshotLimit()
+	var oldData []byte
+	if snapshotLimit > 0 {
+		oldData, _ = os.ReadFile(configurations.CfgPath)
+	}
 	err = os.WriteFile(configurations.CfgPath, data, 0644)
 	if err != nil {
 		return errors.Wrap(err, "failed to write the config to file")
 	}
+	if snapshotLimit > 0 {
+		recordConfigSnapshot(configurations.CfgPath, oldData, data, snapshotLimit)
+	}
 	return nil
 }
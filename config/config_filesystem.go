@@ -28,8 +28,9 @@ var (
 )
 
 // LocalDir returns the local directory in which tanzu state is stored.
+// The directory can be relocated with TANZU_CONFIG_DIR or XDG_CONFIG_HOME (see ResolveDir).
 func LocalDir() (path string, err error) {
-	return localDirPath(LocalDirName)
+	return ResolveDir(DirKindConfig)
 }
 
 // localDirPath returns the full path of the directory name in which tanzu state is stored.
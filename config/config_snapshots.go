@@ -0,0 +1,440 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/internal/atomicfile"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/nodeutils"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+const (
+	// EnvConfigSnapshotsKey is the environment variable overriding the max number of snapshots kept for each
+	// config file, the oldest snapshots are removed when the limit is exceeded. The snapshots are disabled if
+	// it is set to 0. DefaultConfigSnapshotLimit is used if it is not set or is not a number.
+	EnvConfigSnapshotsKey = "TANZU_CONFIG_SNAPSHOTS"
+
+	// DefaultConfigSnapshotLimit is the default max number of snapshots kept for each config file
+	DefaultConfigSnapshotLimit = 10
+
+	// snapshotsDirName is the name of the directory in which the config snapshots are stored
+	snapshotsDirName = "snapshots"
+	// snapshotFileExt is the extension of the config snapshot files
+	snapshotFileExt = ".snapshot"
+)
+
+// configSnapshotSource is the name of the plugin writing the config, defaults to the name of the executable
+var configSnapshotSource = struct {
+	sync.RWMutex
+	name string
+}{name: filepath.Base(os.Args[0])}
+
+// snapshotSeq disambiguates the snapshots taken by the process at the same time
+var snapshotSeq uint64
+
+// ConfigSnapshot is a snapshot of a config file taken when the config file was written
+type ConfigSnapshot struct {
+	// ID of the snapshot
+	ID string `json:"id"`
+	// File is the name of the config file e.g. config.yaml
+	File string `json:"file"`
+	// Path of the config file
+	Path string `json:"path"`
+	// Timestamp of the write of the config file
+	Timestamp time.Time `json:"timestamp"`
+	// Plugin is the name of the plugin which wrote the config file. Empty for the
+	// baseline snapshot of the config file before the first recorded write
+	Plugin string `json:"plugin,omitempty"`
+	// PID of the process which wrote the config file
+	PID int `json:"pid,omitempty"`
+
+	snapshotPath string
+}
+
+// ConfigChangeType is the type of change of a config node
+type ConfigChangeType string
+
+const (
+	// ConfigChangeAdded indicates the node has been added
+	ConfigChangeAdded ConfigChangeType = "added"
+	// ConfigChangeRemoved indicates the node has been removed
+	ConfigChangeRemoved ConfigChangeType = "removed"
+	// ConfigChangeModified indicates the value of the node has been modified
+	ConfigChangeModified ConfigChangeType = "modified"
+)
+
+// ConfigChange is a change of a config node between a snapshot and the current config
+type ConfigChange struct {
+	// Path is the JSON Pointer (RFC 6901) of the node e.g. /clientOptions/cli/discoverySources/0
+	Path string `json:"path"`
+	// Type of the change
+	Type ConfigChangeType `json:"type"`
+	// OldValue is the yaml value of the node in the snapshot
+	OldValue string `json:"oldValue,omitempty"`
+	// NewValue is the yaml value of the node in the current config
+	NewValue string `json:"newValue,omitempty"`
+}
+
+// SetConfigSnapshotSource sets the name of the plugin recorded in the config snapshots taken by the process
+func SetConfigSnapshotSource(name string) {
+	configSnapshotSource.Lock()
+	defer configSnapshotSource.Unlock()
+	configSnapshotSource.name = name
+}
+
+func getConfigSnapshotSource() string {
+	configSnapshotSource.RLock()
+	defer configSnapshotSource.RUnlock()
+	return configSnapshotSource.name
+}
+
+// ListConfigSnapshots returns the snapshots of config.yaml, config-ng.yaml and the config metadata
+// file sorted from the most recent to the oldest
+func ListConfigSnapshots() ([]*ConfigSnapshot, error) {
+	paths, err := snapshottedConfigPaths()
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*ConfigSnapshot
+	for _, path := range paths {
+		fileSnapshots, err := readConfigSnapshots(path)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, fileSnapshots...)
+	}
+	sortConfigSnapshots(snapshots)
+	return snapshots, nil
+}
+
+// DiffConfigSnapshot returns the changes between the snapshot and the current content of the config file
+func DiffConfigSnapshot(id string) ([]ConfigChange, error) {
+	snapshot, err := getConfigSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+	oldNode, err := readConfigSnapshotNode(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(snapshot.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read the config file %q", snapshot.Path)
+	}
+	newNode, err := unmarshalSnapshotNode(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the config file %q", snapshot.Path)
+	}
+
+	return toConfigChanges(nodeutils.Diff(oldNode, newNode)), nil
+}
+
+// RestoreConfigSnapshot restores the config file to the content of the snapshot. The restore is
+// recorded as a new snapshot and can be reverted by restoring the previous snapshot.
+func RestoreConfigSnapshot(id string) error {
+	snapshot, err := getConfigSnapshot(id)
+	if err != nil {
+		return err
+	}
+	content, err := readConfigSnapshotContent(snapshot.snapshotPath)
+	if err != nil {
+		return err
+	}
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(content, node); err != nil {
+		return errors.Wrapf(err, "failed to parse the config snapshot %q", id)
+	}
+
+	metadataPath, err := CfgMetadataFilePath()
+	if err != nil {
+		return err
+	}
+	if snapshot.Path == metadataPath {
+		AcquireTanzuMetadataLock()
+		defer ReleaseTanzuMetadataLock()
+	} else {
+		AcquireTanzuConfigLock()
+		defer ReleaseTanzuConfigLock()
+	}
+	return persistNode(node, WithCfgPath(snapshot.Path))
+}
+
+// recordConfigSnapshot records the snapshot of the config file written with the new content and keeps the
+// limit most recent snapshots. A baseline snapshot of the previous content is recorded first if the config
+// file has no snapshot yet. Failures are logged and don't fail the write of the config file.
+func recordConfigSnapshot(cfgPath string, oldContent, newContent []byte, limit int) {
+	if bytes.Equal(oldContent, newContent) {
+		return
+	}
+	if err := recordConfigSnapshotE(cfgPath, oldContent, newContent, limit); err != nil {
+		log.V(6).Infof("failed to record the snapshot of the config file %q: %v", cfgPath, err)
+	}
+}
+
+// getConfigSnapshotLimit returns the max number of snapshots kept for each config file, 0 if the snapshots are disabled
+func getConfigSnapshotLimit() int {
+	limit, err := strconv.Atoi(strings.TrimSpace(os.Getenv(EnvConfigSnapshotsKey)))
+	if err != nil || limit < 0 {
+		return DefaultConfigSnapshotLimit
+	}
+	return limit
+}
+
+func recordConfigSnapshotE(cfgPath string, oldContent, newContent []byte, limit int) error {
+	dir := configSnapshotsDir(cfgPath)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	snapshotFiles, err := listConfigSnapshotFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if len(snapshotFiles) == 0 && len(oldContent) != 0 {
+		baseline := newConfigSnapshot(cfgPath, now, "", 0)
+		if err := writeConfigSnapshot(dir, baseline, oldContent); err != nil {
+			return err
+		}
+		snapshotFiles = append(snapshotFiles, filepath.Base(baseline.snapshotPath))
+	}
+	snapshot := newConfigSnapshot(cfgPath, now, getConfigSnapshotSource(), os.Getpid())
+	if err := writeConfigSnapshot(dir, snapshot, newContent); err != nil {
+		return err
+	}
+	snapshotFiles = append(snapshotFiles, filepath.Base(snapshot.snapshotPath))
+
+	// Remove the oldest snapshots exceeding the limit
+	sortConfigSnapshotFiles(snapshotFiles)
+	if len(snapshotFiles) <= limit {
+		return nil
+	}
+	for _, name := range snapshotFiles[limit:] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// listConfigSnapshotFiles returns the names of the snapshot files in the directory
+func listConfigSnapshotFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the config snapshots directory")
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), snapshotFileExt) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// sortConfigSnapshotFiles sorts the snapshot files from the most recent to the oldest using the timestamp
+// and the sequence number of the snapshot IDs, without reading the snapshot files
+func sortConfigSnapshotFiles(names []string) {
+	type snapshotKey struct {
+		nanos, seq int64
+	}
+	parseKey := func(name string) snapshotKey {
+		parts := strings.Split(strings.TrimSuffix(name, snapshotFileExt), "-")
+		if len(parts) != 3 {
+			return snapshotKey{}
+		}
+		nanos, _ := strconv.ParseInt(parts[0], 10, 64)
+		seq, _ := strconv.ParseInt(parts[2], 10, 64)
+		return snapshotKey{nanos: nanos, seq: seq}
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		ki, kj := parseKey(names[i]), parseKey(names[j])
+		if ki.nanos == kj.nanos {
+			return ki.seq > kj.seq
+		}
+		return ki.nanos > kj.nanos
+	})
+}
+
+func newConfigSnapshot(cfgPath string, timestamp time.Time, plugin string, pid int) *ConfigSnapshot {
+	seq := atomic.AddUint64(&snapshotSeq, 1)
+	return &ConfigSnapshot{
+		ID:        fmt.Sprintf("%d-%d-%d", timestamp.UnixNano(), os.Getpid(), seq),
+		File:      filepath.Base(cfgPath),
+		Path:      cfgPath,
+		Timestamp: timestamp,
+		Plugin:    plugin,
+		PID:       pid,
+	}
+}
+
+// writeConfigSnapshot writes the snapshot file with the snapshot metadata on the first line followed by the content
+func writeConfigSnapshot(dir string, snapshot *ConfigSnapshot, content []byte) error {
+	header, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	data := make([]byte, 0, len(header)+1+len(content))
+	data = append(append(append(data, header...), '\n'), content...)
+	snapshot.snapshotPath = filepath.Join(dir, snapshot.ID+snapshotFileExt)
+	return atomicfile.WriteFile(snapshot.snapshotPath, data, 0o600)
+}
+
+// readConfigSnapshots reads the metadata of the snapshots of the config file
+func readConfigSnapshots(cfgPath string) ([]*ConfigSnapshot, error) {
+	dir := configSnapshotsDir(cfgPath)
+	names, err := listConfigSnapshotFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []*ConfigSnapshot
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// The snapshot has been removed meanwhile
+			continue
+		}
+		header, _, _ := bytes.Cut(data, []byte("\n"))
+		snapshot := &ConfigSnapshot{}
+		if err := json.Unmarshal(header, snapshot); err != nil || snapshot.Path != cfgPath {
+			continue
+		}
+		snapshot.snapshotPath = path
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// readConfigSnapshotContent returns the content of the config file stored in the snapshot file
+func readConfigSnapshotContent(snapshotPath string) ([]byte, error) {
+	data, err := os.ReadFile(snapshotPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read the config snapshot")
+	}
+	_, content, _ := bytes.Cut(data, []byte("\n"))
+	return content, nil
+}
+
+func readConfigSnapshotNode(snapshot *ConfigSnapshot) (*yaml.Node, error) {
+	content, err := readConfigSnapshotContent(snapshot.snapshotPath)
+	if err != nil {
+		return nil, err
+	}
+	node, err := unmarshalSnapshotNode(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the config snapshot %q", snapshot.ID)
+	}
+	return node, nil
+}
+
+// unmarshalSnapshotNode returns the top level node of the yaml document, nil if the document is empty
+func unmarshalSnapshotNode(data []byte) (*yaml.Node, error) {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, err
+	}
+	if node.Kind != yaml.DocumentNode || len(node.Content) == 0 {
+		return nil, nil
+	}
+	return node.Content[0], nil
+}
+
+func getConfigSnapshot(id string) (*ConfigSnapshot, error) {
+	snapshots, err := ListConfigSnapshots()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range snapshots {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, errors.Errorf("config snapshot %q not found", id)
+}
+
+// snapshottedConfigPaths returns the paths of the config files for which snapshots are recorded
+func snapshottedConfigPaths() ([]string, error) {
+	cfgPath, err := ClientConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	cfgNextGenPath, err := ClientConfigNextGenPath()
+	if err != nil {
+		return nil, err
+	}
+	metadataPath, err := CfgMetadataFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return []string{cfgPath, cfgNextGenPath, metadataPath}, nil
+}
+
+// configSnapshotsDir returns the directory of the snapshots of the config file. The snapshots are stored in the
+// state directory if it is specified with an environment variable and next to the config file otherwise.
+func configSnapshotsDir(cfgPath string) string {
+	return filepath.Join(statePath(cfgPath, snapshotsDirName), filepath.Base(cfgPath))
+}
+
+// sortConfigSnapshots sorts the snapshots from the most recent to the oldest
+func sortConfigSnapshots(snapshots []*ConfigSnapshot) {
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		if snapshots[i].Timestamp.Equal(snapshots[j].Timestamp) {
+			// The baseline snapshot is older than the snapshot recorded at the same time
+			return snapshots[i].PID != 0 && snapshots[j].PID == 0
+		}
+		return snapshots[i].Timestamp.After(snapshots[j].Timestamp)
+	})
+}
+
+// toConfigChanges converts the changes between two config nodes to config changes
+func toConfigChanges(changes []nodeutils.Change) []ConfigChange {
+	configChanges := make([]ConfigChange, 0, len(changes))
+	for _, change := range changes {
+		configChange := ConfigChange{Path: change.Path}
+		switch change.Op {
+		case nodeutils.ChangeOpAdd:
+			configChange.Type = ConfigChangeAdded
+		case nodeutils.ChangeOpRemove:
+			configChange.Type = ConfigChangeRemoved
+		default:
+			configChange.Type = ConfigChangeModified
+		}
+		if change.OldValue != nil {
+			configChange.OldValue = nodeToYAML(change.OldValue)
+		}
+		if change.Value != nil {
+			configChange.NewValue = nodeToYAML(change.Value)
+		}
+		configChanges = append(configChanges, configChange)
+	}
+	return configChanges
+}
+
+func nodeToYAML(node *yaml.Node) string {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(data), "\n")
+}
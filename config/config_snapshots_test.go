@@ -0,0 +1,212 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/nodeutils"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestConfigSnapshots(t *testing.T) {
+	cfg := `clientOptions:
+  cli:
+    edition: tkg
+`
+	files, cleanUp := setupTestConfig(t, &CfgTestData{cfg: cfg})
+	defer cleanUp()
+	t.Setenv(EnvConfigSnapshotsKey, "20")
+	SetConfigSnapshotSource("test-plugin")
+	defer SetConfigSnapshotSource("")
+
+	snapshots, err := ListConfigSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	require.NoError(t, SetEnv("SNAPSHOT_TEST", "a"))
+	require.NoError(t, SetEnv("SNAPSHOT_TEST", "b"))
+	require.NoError(t, SetCLIDiscoverySource(configtypes.PluginDiscovery{
+		OCI: &configtypes.OCIDiscovery{Name: "default", Image: "example.com/default:latest"},
+	}))
+
+	snapshots, err = ListConfigSnapshots()
+	require.NoError(t, err)
+
+	// The baseline and the snapshots of both SetEnv in config.yaml. The config-ng.yaml had
+	// no content before, so it has no baseline
+	cfgSnapshots := filterConfigSnapshots(snapshots, files[0].Name())
+	require.Len(t, cfgSnapshots, 3)
+	assert.NotEmpty(t, filterConfigSnapshots(snapshots, files[1].Name()))
+	assert.Empty(t, filterConfigSnapshots(snapshots, files[2].Name()))
+	assert.Equal(t, "", cfgSnapshots[2].Plugin)
+	assert.Equal(t, 0, cfgSnapshots[2].PID)
+	for _, s := range cfgSnapshots[:2] {
+		assert.Equal(t, "test-plugin", s.Plugin)
+		assert.Equal(t, os.Getpid(), s.PID)
+	}
+
+	// Diff from the baseline to the current config
+	changes, err := DiffConfigSnapshot(cfgSnapshots[2].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigChange{
+		{Path: "/clientOptions/env", Type: ConfigChangeAdded, NewValue: "SNAPSHOT_TEST: b"},
+	}, changes)
+
+	changes, err = DiffConfigSnapshot(cfgSnapshots[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigChange{
+		{Path: "/clientOptions/env/SNAPSHOT_TEST", Type: ConfigChangeModified, OldValue: "a", NewValue: "b"},
+	}, changes)
+
+	// No change from the latest snapshot
+	changes, err = DiffConfigSnapshot(cfgSnapshots[0].ID)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// Restore the snapshot taken after the first SetEnv
+	require.NoError(t, RestoreConfigSnapshot(cfgSnapshots[1].ID))
+	env, err := GetEnv("SNAPSHOT_TEST")
+	require.NoError(t, err)
+	assert.Equal(t, "a", env)
+	ds, err := GetCLIDiscoverySource("default")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com/default:latest", ds.OCI.Image)
+
+	// The restore is recorded as a new snapshot
+	snapshots, err = ListConfigSnapshots()
+	require.NoError(t, err)
+	assert.Len(t, filterConfigSnapshots(snapshots, files[0].Name()), 4)
+
+	_, err = DiffConfigSnapshot("missing")
+	assert.EqualError(t, err, `config snapshot "missing" not found`)
+}
+
+func filterConfigSnapshots(snapshots []*ConfigSnapshot, path string) []*ConfigSnapshot {
+	var filtered []*ConfigSnapshot
+	for _, s := range snapshots {
+		if s.Path == path {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+func TestConfigSnapshotLimit(t *testing.T) {
+	files, cleanUp := setupTestConfig(t, &CfgTestData{})
+	defer cleanUp()
+
+	t.Setenv(EnvConfigSnapshotsKey, "3")
+
+	for _, value := range []string{"a", "b", "c", "d", "e"} {
+		require.NoError(t, SetEnv("SNAPSHOT_TEST", value))
+	}
+
+	snapshots, err := readConfigSnapshots(files[0].Name())
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 3)
+
+	// The oldest snapshots are removed
+	sortConfigSnapshots(snapshots)
+	require.NoError(t, RestoreConfigSnapshot(snapshots[2].ID))
+	env, err := GetEnv("SNAPSHOT_TEST")
+	require.NoError(t, err)
+	assert.Equal(t, "c", env)
+
+	t.Setenv(EnvConfigSnapshotsKey, "0")
+	require.NoError(t, SetEnv("SNAPSHOT_TEST", "f"))
+	snapshots, err = readConfigSnapshots(files[0].Name())
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 3)
+}
+
+func TestConfigSnapshotsDefaultLimit(t *testing.T) {
+	files, cleanUp := setupTestConfig(t, &CfgTestData{cfg: "clientOptions: {}\n"})
+	defer cleanUp()
+	t.Setenv(EnvConfigSnapshotsKey, "")
+
+	// The baseline snapshot and the snapshots of the writes are kept up to the default limit
+	for i := 0; i < DefaultConfigSnapshotLimit+2; i++ {
+		require.NoError(t, SetEnv("SNAPSHOT_TEST", strconv.Itoa(i)))
+	}
+	snapshots, err := readConfigSnapshots(files[0].Name())
+	require.NoError(t, err)
+	assert.Len(t, snapshots, DefaultConfigSnapshotLimit)
+
+	// An invalid value falls back to the default limit
+	t.Setenv(EnvConfigSnapshotsKey, "invalid")
+	assert.Equal(t, DefaultConfigSnapshotLimit, getConfigSnapshotLimit())
+}
+
+func TestSortConfigSnapshotFiles(t *testing.T) {
+	names := []string{"100-1-2.snapshot", "300-2-1.snapshot", "100-1-1.snapshot", "200-1-3.snapshot", "invalid.snapshot"}
+	sortConfigSnapshotFiles(names)
+	assert.Equal(t, []string{"300-2-1.snapshot", "200-1-3.snapshot", "100-1-2.snapshot", "100-1-1.snapshot", "invalid.snapshot"}, names)
+}
+
+func TestToConfigChanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected []ConfigChange
+	}{
+		{
+			name:     "should report no change for equal documents with different key order",
+			old:      "a: 1\nb:\n  c: x\n",
+			new:      "b:\n  c: x\na: 1\n",
+			expected: []ConfigChange{},
+		},
+		{
+			name: "should report nested added, removed and modified keys",
+			old:  "a: 1\nb:\n  c: x\n  d: y\n",
+			new:  "a: 2\nb:\n  c: x\n  e: z\n",
+			expected: []ConfigChange{
+				{Path: "/a", Type: ConfigChangeModified, OldValue: "1", NewValue: "2"},
+				{Path: "/b/d", Type: ConfigChangeRemoved, OldValue: "y"},
+				{Path: "/b/e", Type: ConfigChangeAdded, NewValue: "z"},
+			},
+		},
+		{
+			name: "should report the whole document added",
+			old:  "",
+			new:  "a: 1\n",
+			expected: []ConfigChange{
+				{Path: "", Type: ConfigChangeAdded, NewValue: "a: 1"},
+			},
+		},
+		{
+			name: "should report a change of kind as modified",
+			old:  "a:\n  b: 1\n",
+			new:  "a: [1]\n",
+			expected: []ConfigChange{
+				{Path: "/a", Type: ConfigChangeModified, OldValue: "b: 1", NewValue: "[1]"},
+			},
+		},
+		{
+			name: "should report the changes of sequence elements matched by name",
+			old:  "contexts:\n  - name: a\n    target: k8s\n  - name: b\n    target: k8s\n",
+			new:  "contexts:\n  - name: b\n    target: tmc\n",
+			expected: []ConfigChange{
+				{Path: "/contexts/0", Type: ConfigChangeRemoved, OldValue: "name: a\ntarget: k8s"},
+				{Path: "/contexts/0/target", Type: ConfigChangeModified, OldValue: "k8s", NewValue: "tmc"},
+			},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			oldNode, err := unmarshalSnapshotNode([]byte(spec.old))
+			require.NoError(t, err)
+			newNode, err := unmarshalSnapshotNode([]byte(spec.new))
+			require.NoError(t, err)
+
+			assert.Equal(t, spec.expected, toConfigChanges(nodeutils.Diff(oldNode, newNode)))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/nodeutils"
+)
+
+// GetValue retrieves the value of the config at the path expression. The path expression is made of dot separated
+// keys, each optionally followed by selectors of list elements, either by index or by the value of a key.
+// Ex: contexts[name=prod].clusterOpts.context, clientOptions.cli.discoverySources[0]
+// Scalars are returned as string, bool, int or float64, maps as map[string]interface{} and lists as []interface{}.
+func GetValue(path string) (interface{}, error) {
+	// Retrieve client config node
+	node, err := getClientConfigNode()
+	if err != nil {
+		return nil, err
+	}
+	return getValue(node, path)
+}
+
+func getValue(node *yaml.Node, path string) (interface{}, error) {
+	valueNode, err := nodeutils.GetPathNode(node, path)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := valueNode.Decode(&value); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode the value of %q", path)
+	}
+	return value, nil
+}
+
+// SetValue add or update the value of the config at the path expression (see GetValue). The missing keys and
+// the list elements selected by the value of a key are created. Maps are merged with the existing value as per
+// the config metadata patch strategies and any other value replaces the existing value. The value is stored in
+// config.yaml or config-ng.yaml depending on the top level key of the path.
+func SetValue(path string, value interface{}) error {
+	// Retrieve client config node
+	AcquireTanzuConfigLock()
+	defer ReleaseTanzuConfigLock()
+	node, err := getClientConfigNodeNoLock()
+	if err != nil {
+		return err
+	}
+
+	persist, err := setValue(node, path, value)
+	if err != nil {
+		return err
+	}
+	if persist {
+		return persistConfig(node)
+	}
+	return nil
+}
+
+func setValue(node *yaml.Node, path string, value interface{}) (persist bool, err error) {
+	valueNode, err := convertValueToNode(value)
+	if err != nil {
+		return false, err
+	}
+
+	// Get Patch Strategies
+	patchStrategies := constructPatchStrategies()
+
+	return nodeutils.SetPathNode(node, path, valueNode, nodeutils.WithPatchStrategies(patchStrategies))
+}
+
+// convertValueToNode converts a value to yaml node
+func convertValueToNode(value interface{}) (*yaml.Node, error) {
+	if node, ok := value.(*yaml.Node); ok {
+		return node, nil
+	}
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert value to node")
+	}
+	var node yaml.Node
+	err = yaml.Unmarshal(bytes, &node)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bytes to node")
+	}
+	return &node, nil
+}
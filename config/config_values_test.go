@@ -0,0 +1,85 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSetValue(t *testing.T) {
+	cfg := `clientOptions:
+  cli:
+    # edition of the cli
+    edition: tkg
+`
+	cfgNextGen := `contexts:
+  - name: prod
+    target: kubernetes
+    clusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+`
+	files, cleanup := setupTestConfig(t, &CfgTestData{cfg: cfg, cfgNextGen: cfgNextGen})
+	defer cleanup()
+
+	value, err := GetValue("contexts[name=prod].clusterOpts.context")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-ctx", value)
+
+	value, err = GetValue("contexts[0].clusterOpts")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"context": "prod-ctx", "path": "/tmp/kubeconfig"}, value)
+
+	_, err = GetValue("contexts[name=dev]")
+	assert.EqualError(t, err, `"contexts[name=dev]": node not found`)
+
+	// Values are written to config.yaml or config-ng.yaml by top level key
+	require.NoError(t, SetValue("contexts[name=prod].clusterOpts.context", "prod-ctx-2"))
+	require.NoError(t, SetValue("clientOptions.cli.edition", "tce"))
+	require.NoError(t, SetValue("clientOptions.env", map[string]string{"FOO": "bar"}))
+
+	ctx, err := GetContext("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "prod-ctx-2", ctx.ClusterOpts.Context)
+	assert.Equal(t, "/tmp/kubeconfig", ctx.ClusterOpts.Path)
+
+	envValue, err := GetEnv("FOO")
+	require.NoError(t, err)
+	assert.Equal(t, "bar", envValue)
+
+	data, err := os.ReadFile(files[0].Name())
+	require.NoError(t, err)
+	assert.Equal(t, `clientOptions:
+    cli:
+        # edition of the cli
+        edition: tce
+    env:
+        FOO: bar
+`, string(data))
+
+	data, err = os.ReadFile(files[1].Name())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "context: prod-ctx-2")
+	assert.NotContains(t, string(data), "clientOptions")
+
+	// Maps are merged unless the patch strategy is replace
+	require.NoError(t, SetValue("contexts[name=prod].additionalMetadata", map[string]interface{}{"a": "b"}))
+	require.NoError(t, SetValue("contexts[name=prod].additionalMetadata", map[string]interface{}{"c": "d"}))
+	value, err = GetValue("contexts[name=prod].additionalMetadata")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"c": "d"}, value)
+
+	require.NoError(t, SetConfigMetadataPatchStrategy("contexts.additionalMetadata", "merge"))
+	require.NoError(t, SetValue("contexts[name=prod].additionalMetadata", map[string]interface{}{"a": "b"}))
+	value, err = GetValue("contexts[name=prod].additionalMetadata")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "b", "c": "d"}, value)
+
+	err = SetValue("contexts[name=prod].clusterOpts.context[0]", "x")
+	assert.EqualError(t, err, `failed to set "contexts[name=prod].clusterOpts.context[0]": cannot select [0] of a node which is not a sequence`)
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// contextOverride holds the name of the context overriding the active context for the current process
+var contextOverride = struct {
+	sync.RWMutex
+	name string
+}{}
+
+// SetContextOverride overrides the active context with the named context for the current process.
+// The override is not persisted to the config. The plugins set it with the --context flag or the TANZU_CONTEXT
+// environment variable.
+// The context is active along with the active contexts of the other context types, except that it
+// deactivates the active contexts which are mutually exclusive with it, as SetActiveContext does.
+func SetContextOverride(name string) {
+	contextOverride.Lock()
+	defer contextOverride.Unlock()
+	contextOverride.name = name
+}
+
+// ResetContextOverride removes the context override set with SetContextOverride
+func ResetContextOverride() {
+	SetContextOverride("")
+}
+
+// GetContextOverride returns the name of the context overriding the active context for the current process
+// set with SetContextOverride, empty if not overridden.
+func GetContextOverride() string {
+	contextOverride.RLock()
+	defer contextOverride.RUnlock()
+	return contextOverride.name
+}
+
+// convertNodeToClientConfigWithContextOverride converts the node to a ClientConfig with the current contexts
+// updated with the context override. The returned ClientConfig must not be persisted.
+// A context override which does not exist is ignored, the plugins report it when the override is applied.
+func convertNodeToClientConfigWithContextOverride(node *yaml.Node) (*configtypes.ClientConfig, error) {
+	cfg, err := convertNodeToClientConfig(node)
+	if err != nil {
+		return nil, err
+	}
+	name := GetContextOverride()
+	if name == "" {
+		return cfg, nil
+	}
+	ctx, err := cfg.GetContext(name)
+	if err != nil {
+		return cfg, nil
+	}
+	if cfg.CurrentContext == nil {
+		cfg.CurrentContext = make(map[configtypes.ContextType]string)
+	}
+	// Deactivate the contexts which are mutually exclusive with the override as updateMutualExclusiveCurrentContexts does
+	if ctx.ContextType != configtypes.ContextTypeTMC {
+		for contextType := range cfg.CurrentContext {
+			if contextType != configtypes.ContextTypeTMC {
+				delete(cfg.CurrentContext, contextType)
+			}
+		}
+	}
+	cfg.CurrentContext[ctx.ContextType] = ctx.Name
+	return cfg, nil
+}
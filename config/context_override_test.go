@@ -0,0 +1,90 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestContextOverride(t *testing.T) {
+	cfgNextGen := `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+  - name: staging
+    contextType: tanzu
+    clusterOpts:
+      context: staging-ctx
+      path: /tmp/kubeconfig
+  - name: tmc
+    target: mission-control
+    contextType: mission-control
+    globalOpts:
+      endpoint: test-endpoint
+currentContext:
+  kubernetes: prod
+  mission-control: tmc
+`
+	files, cleanup := setupTestConfig(t, &CfgTestData{cfgNextGen: cfgNextGen})
+	defer cleanup()
+	defer ResetContextOverride()
+
+	assert.Empty(t, GetContextOverride())
+	ctx, err := GetActiveContext(configtypes.ContextTypeK8s)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", ctx.Name)
+
+	// The override takes precedence over the active context
+	SetContextOverride("staging")
+	assert.Equal(t, "staging", GetContextOverride())
+	ctx, err = GetActiveContext(configtypes.ContextTypeTanzu)
+	require.NoError(t, err)
+	assert.Equal(t, "staging", ctx.Name)
+	_, err = GetActiveContext(configtypes.ContextTypeK8s)
+	assert.Error(t, err)
+	contexts, err := GetAllActiveContextsMap()
+	require.NoError(t, err)
+	assert.Len(t, contexts, 2)
+	assert.Equal(t, "staging", contexts[configtypes.ContextTypeTanzu].Name)
+	assert.Equal(t, "tmc", contexts[configtypes.ContextTypeTMC].Name)
+
+	SetContextOverride("prod")
+	assert.Equal(t, "prod", GetContextOverride())
+	ctx, err = GetActiveContext(configtypes.ContextTypeK8s)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", ctx.Name)
+	_, err = GetActiveContext(configtypes.ContextTypeTanzu)
+	assert.Error(t, err)
+
+	// The TANZU_CONTEXT environment variable is applied by the plugins, not by the config
+	ResetContextOverride()
+	t.Setenv("TANZU_CONTEXT", "staging")
+	assert.Empty(t, GetContextOverride())
+	ctx, err = GetActiveContext(configtypes.ContextTypeK8s)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", ctx.Name)
+
+	// An unknown context is ignored
+	SetContextOverride("unknown")
+	ctx, err = GetActiveContext(configtypes.ContextTypeK8s)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", ctx.Name)
+	contexts, err = GetAllActiveContextsMap()
+	require.NoError(t, err)
+	assert.Len(t, contexts, 2)
+
+	// The config file is never updated
+	b, err := os.ReadFile(files[1].Name())
+	require.NoError(t, err)
+	assert.Equal(t, cfgNextGen, string(b))
+}
@@ -164,7 +164,11 @@ func GetActiveContext(contextType configtypes.ContextType) (c *configtypes.Conte
 	if err != nil {
 		return nil, err
 	}
-	return getActiveContext(node, contextType)
+	cfg, err := convertNodeToClientConfigWithContextOverride(node)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.GetActiveContext(contextType)
 }
 
 // GetContextsByType retrieves the contexts of a provided context type
@@ -196,7 +200,11 @@ func GetAllCurrentContextsMap() (map[configtypes.Target]*configtypes.Context, er
 	if err != nil {
 		return nil, err
 	}
-	return getAllCurrentContextsMap(node)
+	cfg, err := convertNodeToClientConfigWithContextOverride(node)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.GetAllCurrentContextsMap()
 }
 
 // GetAllActiveContextsMap returns all active context per ContextType
@@ -205,7 +213,11 @@ func GetAllActiveContextsMap() (map[configtypes.ContextType]*configtypes.Context
 	if err != nil {
 		return nil, err
 	}
-	return getAllActiveContextsMap(node)
+	cfg, err := convertNodeToClientConfigWithContextOverride(node)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.GetAllActiveContextsMap()
 }
 
 // GetAllActiveContextsList returns all active context names as list
@@ -416,7 +428,7 @@ func setContext(node *yaml.Node, ctx *configtypes.Context) (persist bool, err er
 			if err != nil {
 				return false, err
 			}
-			persist, err = nodeutils.MergeNodes(newContextNode.Content[0], contextNode)
+			persist, err = nodeutils.MergeNodes(newContextNode.Content[0], contextNode, nodeutils.WithPatchStrategyKey(KeyContexts), nodeutils.WithPatchStrategies(patchStrategies))
 			if err != nil {
 				return false, err
 			}
@@ -426,7 +438,7 @@ func setContext(node *yaml.Node, ctx *configtypes.Context) (persist bool, err er
 			}
 			// merge the discovery sources to context
 			if persistDiscoverySources {
-				_, err = nodeutils.MergeNodes(newContextNode.Content[0], contextNode)
+				_, err = nodeutils.MergeNodes(newContextNode.Content[0], contextNode, nodeutils.WithPatchStrategyKey(KeyContexts), nodeutils.WithPatchStrategies(patchStrategies))
 				if err != nil {
 					return false, err
 				}
@@ -652,3 +652,46 @@ func TestContextAdditionalMetadataStringToStructArray(t *testing.T) {
 		})
 	}
 }
+
+func TestContextAdditionalMetadataListPatchStrategies(t *testing.T) {
+	// Setup config data
+	cfgMetadata := `configMetadata:
+  patchStrategy:
+    contexts.additionalMetadata: merge
+    contexts.additionalMetadata.*: set-union
+    contexts.additionalMetadata.issuers: merge:name
+`
+	_, cleanUp := setupTestConfig(t, &CfgTestData{cfgNextGen: ``, cfg: ``, cfgMetadata: cfgMetadata})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	setContext := func(additionalMetadata map[string]interface{}) {
+		err := SetContext(&configtypes.Context{
+			Name:               "test-mc",
+			Target:             configtypes.TargetK8s,
+			ClusterOpts:        &configtypes.ClusterServer{Endpoint: "test-endpoint"},
+			AdditionalMetadata: additionalMetadata,
+		}, false)
+		assert.NoError(t, err)
+	}
+	setContext(map[string]interface{}{
+		"tags":    []string{"a", "b"},
+		"issuers": []map[string]interface{}{{"name": "vmw1", "url": "https://vmw1"}},
+	})
+	setContext(map[string]interface{}{
+		"tags":    []string{"b", "c"},
+		"issuers": []map[string]interface{}{{"name": "vmw1", "url": "https://vmw1.new"}, {"name": "vmw2"}},
+	})
+
+	ctx, err := GetContext("test-mc")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"tags": []interface{}{"a", "b", "c"},
+		"issuers": []interface{}{
+			map[string]interface{}{"name": "vmw1", "url": "https://vmw1.new"},
+			map[string]interface{}{"name": "vmw2"},
+		},
+	}, ctx.AdditionalMetadata)
+}
@@ -28,6 +28,19 @@ const (
 	Default = "default"
 )
 
+// discoverySourceTypeKeys maps the discovery types to their keys in the yaml node, if they differ
+var discoverySourceTypeKeys = map[string]string{
+	DiscoveryTypeKubernetes: "k8s",
+}
+
+// getDiscoverySourceTypeKey returns the key of the discovery type in the yaml node
+func getDiscoverySourceTypeKey(discoverySourceType string) string {
+	if key, ok := discoverySourceTypeKeys[discoverySourceType]; ok {
+		return key
+	}
+	return discoverySourceType
+}
+
 // setDiscoverySources adds or updates the node discoverySources
 func setDiscoverySources(node *yaml.Node, discoverySources []configtypes.PluginDiscovery, patchStrategyOpts ...nodeutils.PatchStrategyOpts) (persist bool, err error) {
 	var anyPersists []bool
@@ -75,7 +88,7 @@ func setDiscoverySource(discoverySourcesNode *yaml.Node, discoverySource configt
 		discoverySourceTypeOfAnyType, discoverySourceIndexOfAnyType := findDiscoverySourceTypeAndIndexByWeakMatch(discoverySourceNode.Content)
 
 		// Find discovery source by exact match
-		discoverySourceIndexOfExactType := nodeutils.GetNodeIndex(discoverySourceNode.Content, newOrUpdatedDiscoverySourceType)
+		discoverySourceIndexOfExactType := nodeutils.GetNodeIndex(discoverySourceNode.Content, getDiscoverySourceTypeKey(newOrUpdatedDiscoverySourceType))
 
 		// check if same name already exists
 		nameIdx := nodeutils.GetNodeIndex(discoverySourceNode.Content[discoverySourceIndexOfAnyType].Content, "name")
@@ -92,7 +105,7 @@ func setDiscoverySource(discoverySourcesNode *yaml.Node, discoverySource configt
 					return false, err
 				}
 				// Merge the new node into discovery source node
-				persist, err = nodeutils.MergeNodes(newNode.Content[0], discoverySourceNode)
+				persist, err = nodeutils.MergeNodes(newNode.Content[0], discoverySourceNode, patchStrategyOpts...)
 				if err != nil {
 					return false, err
 				}
@@ -106,7 +119,7 @@ func setDiscoverySource(discoverySourcesNode *yaml.Node, discoverySource configt
 				for _, opt := range patchStrategyOpts {
 					opt(options)
 				}
-				replaceDiscoverySourceTypeKey := fmt.Sprintf("%v.%v", options.Key, discoverySourceTypeOfAnyType)
+				replaceDiscoverySourceTypeKey := fmt.Sprintf("%v.%v", options.Key, getDiscoverySourceTypeKey(discoverySourceTypeOfAnyType))
 				replaceDiscoverySourceContextTypeKey := fmt.Sprintf("%v.%v", options.Key, "contextType")
 				options.PatchStrategies[replaceDiscoverySourceTypeKey] = nodeutils.PatchStrategyReplace
 				options.PatchStrategies[replaceDiscoverySourceContextTypeKey] = nodeutils.PatchStrategyReplace
@@ -117,7 +130,7 @@ func setDiscoverySource(discoverySourcesNode *yaml.Node, discoverySource configt
 					return false, err
 				}
 				// Merge the new node into discovery source node
-				persist, err = nodeutils.MergeNodes(newNode.Content[0], discoverySourceNode)
+				persist, err = nodeutils.MergeNodes(newNode.Content[0], discoverySourceNode, patchStrategyOpts...)
 				if err != nil {
 					return false, err
 				}
@@ -169,7 +182,7 @@ func getDiscoverySourceTypeAndName(discoverySource configtypes.PluginDiscovery)
 func findDiscoverySourceTypeAndIndexByWeakMatch(discoverySourceContentNodes []*yaml.Node) (string, int) {
 	acceptedDiscoverySources := []string{DiscoveryTypeOCI, DiscoveryTypeLocal, DiscoveryTypeGCP, DiscoveryTypeKubernetes, DiscoveryTypeREST}
 	for _, discoverySourceType := range acceptedDiscoverySources {
-		idx := nodeutils.GetNodeIndex(discoverySourceContentNodes, discoverySourceType)
+		idx := nodeutils.GetNodeIndex(discoverySourceContentNodes, getDiscoverySourceTypeKey(discoverySourceType))
 		if idx != -1 {
 			return discoverySourceType, idx
 		}
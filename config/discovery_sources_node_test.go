@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v3"
 
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/nodeutils"
 	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 )
 
@@ -50,3 +51,31 @@ func TestSetDiscoverySource(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDiscoverySourcesKubernetes(t *testing.T) {
+	discoverySource := configtypes.PluginDiscovery{
+		Kubernetes: &configtypes.KubernetesDiscovery{
+			Name:    "test-k8s",
+			Path:    "test-path",
+			Context: "test-context",
+		},
+	}
+	node := &yaml.Node{}
+	persist, err := setDiscoverySources(node, []configtypes.PluginDiscovery{discoverySource})
+	assert.NoError(t, err)
+	assert.True(t, persist)
+
+	// Updating the kubernetes discovery source updates the existing source instead of failing to find it
+	discoverySource.Kubernetes.Context = "updated-context"
+	persist, err = setDiscoverySources(node, []configtypes.PluginDiscovery{discoverySource})
+	assert.NoError(t, err)
+	assert.True(t, persist)
+
+	discoverySourcesNode := nodeutils.FindNode(node, nodeutils.WithKeys([]nodeutils.Key{{Name: KeyDiscoverySources}}))
+	assert.NotNil(t, discoverySourcesNode)
+	var discoverySources []configtypes.PluginDiscovery
+	assert.NoError(t, discoverySourcesNode.Decode(&discoverySources))
+	assert.Len(t, discoverySources, 1)
+	assert.Equal(t, "test-k8s", discoverySources[0].Kubernetes.Name)
+	assert.Equal(t, "updated-context", discoverySources[0].Kubernetes.Context)
+}
@@ -0,0 +1,350 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/nodeutils"
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// DoctorIssueType is the class of an inconsistency of the tanzu configuration detected by Doctor
+type DoctorIssueType string
+
+const (
+	// DoctorIssueDuplicateContext indicates several contexts have the same name
+	DoctorIssueDuplicateContext DoctorIssueType = "duplicate-context"
+	// DoctorIssueMissingContextType indicates a context has no context type
+	DoctorIssueMissingContextType DoctorIssueType = "missing-context-type"
+	// DoctorIssueServerWithoutContext indicates a server has no matching context
+	DoctorIssueServerWithoutContext DoctorIssueType = "server-without-context"
+	// DoctorIssueDanglingCurrentContext indicates a current context refers to a missing context or to a context of another type
+	DoctorIssueDanglingCurrentContext DoctorIssueType = "dangling-current-context"
+	// DoctorIssueDanglingCurrentServer indicates the current server refers to a missing server
+	DoctorIssueDanglingCurrentServer DoctorIssueType = "dangling-current-server"
+	// DoctorIssueMutuallyExclusiveCurrentContexts indicates several current contexts are set among the context types except TMC
+	DoctorIssueMutuallyExclusiveCurrentContexts DoctorIssueType = "mutually-exclusive-current-contexts"
+)
+
+// DoctorOptions is a struct that defines the options of Doctor.
+type DoctorOptions struct {
+	Fix bool // Fix indicates whether to repair the issues detected in the tanzu configuration.
+}
+
+// DoctorOption is a function type that applies configuration options to DoctorOptions.
+type DoctorOption func(opts *DoctorOptions)
+
+// WithDoctorFix returns a DoctorOption function that sets the Fix option to true.
+func WithDoctorFix() DoctorOption {
+	return func(opts *DoctorOptions) {
+		opts.Fix = true
+	}
+}
+
+// DoctorIssue is an inconsistency of the tanzu configuration detected by Doctor
+type DoctorIssue struct {
+	// Type is the class of the issue
+	Type DoctorIssueType `json:"type" yaml:"type"`
+	// Message describes the issue
+	Message string `json:"message" yaml:"message"`
+	// Fixed indicates whether the issue has been repaired
+	Fixed bool `json:"fixed" yaml:"fixed"`
+}
+
+// DoctorReport reports the issues of the tanzu configuration detected by Doctor
+type DoctorReport struct {
+	// Issues contains the detected issues, in the order they are checked
+	Issues []DoctorIssue `json:"issues" yaml:"issues"`
+}
+
+// HasIssues returns true if any issue has been detected
+func (r *DoctorReport) HasIssues() bool {
+	return len(r.Issues) > 0
+}
+
+// UnfixedIssues returns the issues which have not been repaired
+func (r *DoctorReport) UnfixedIssues() []DoctorIssue {
+	var issues []DoctorIssue
+	for _, issue := range r.Issues {
+		if !issue.Fixed {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// doctorCheck detects the issues of a class in the config node and repairs them if fix is true
+type doctorCheck func(node *yaml.Node, fix bool) ([]DoctorIssue, error)
+
+// doctorChecks are the checks run by Doctor in order. A check can rely on the repairs of the previous checks
+// e.g. the current contexts are checked after the contexts of the servers are added.
+var doctorChecks = []doctorCheck{
+	checkDuplicateContexts,
+	checkMissingContextTypes,
+	checkServersWithoutContext,
+	checkDanglingCurrentContexts,
+	checkDanglingCurrentServer,
+	checkMutuallyExclusiveCurrentContexts,
+}
+
+// Doctor detects the inconsistencies of the tanzu configuration and returns a report of the issues. With
+// the WithDoctorFix option, the issues are repaired while holding the config lock:
+//   - the duplicates of a context are removed, the first context with the name is kept
+//   - the missing context type of a context is set from its target
+//   - the context of a server without context is added, and set as current if the server is the current server
+//     and there is no current context of the type
+//   - the current contexts referring to a missing context or to a context of another type are removed
+//   - the current server referring to a missing server is removed
+//
+// The mutually exclusive current contexts are only reported, as the current context to keep is not known.
+func Doctor(opts ...DoctorOption) (*DoctorReport, error) {
+	options := new(DoctorOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var node *yaml.Node
+	var err error
+	if options.Fix {
+		AcquireTanzuConfigLock()
+		defer ReleaseTanzuConfigLock()
+		node, err = getClientConfigNodeNoLock()
+	} else {
+		node, err = getClientConfigNode()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := doctor(node, options.Fix)
+	if err != nil {
+		return nil, err
+	}
+	if options.Fix && len(report.UnfixedIssues()) < len(report.Issues) {
+		return report, persistConfig(node)
+	}
+	return report, nil
+}
+
+func doctor(node *yaml.Node, fix bool) (*DoctorReport, error) {
+	report := &DoctorReport{}
+	for _, check := range doctorChecks {
+		issues, err := check(node, fix)
+		if err != nil {
+			return nil, err
+		}
+		report.Issues = append(report.Issues, issues...)
+	}
+	return report, nil
+}
+
+func checkDuplicateContexts(node *yaml.Node, fix bool) ([]DoctorIssue, error) {
+	contextsNode := nodeutils.FindNode(node.Content[0], nodeutils.WithKeys([]nodeutils.Key{{Name: KeyContexts}}))
+	if contextsNode == nil {
+		return nil, nil
+	}
+	var issues []DoctorIssue
+	reported := make(map[string]bool)
+	seen := make(map[string]bool)
+	var contexts []*yaml.Node
+	for _, contextNode := range contextsNode.Content {
+		name := getScalarValue(contextNode, "name")
+		if !seen[name] {
+			seen[name] = true
+			contexts = append(contexts, contextNode)
+			continue
+		}
+		if !reported[name] {
+			reported[name] = true
+			issues = append(issues, DoctorIssue{
+				Type:    DoctorIssueDuplicateContext,
+				Message: fmt.Sprintf("context %q is defined more than once", name),
+				Fixed:   fix,
+			})
+		}
+	}
+	if fix {
+		contextsNode.Content = contexts
+	}
+	return issues, nil
+}
+
+func checkMissingContextTypes(node *yaml.Node, fix bool) ([]DoctorIssue, error) {
+	contextsNode := nodeutils.FindNode(node.Content[0], nodeutils.WithKeys([]nodeutils.Key{{Name: KeyContexts}}))
+	if contextsNode == nil {
+		return nil, nil
+	}
+	var issues []DoctorIssue
+	for _, contextNode := range contextsNode.Content {
+		if getScalarValue(contextNode, "contextType") != "" {
+			continue
+		}
+		name := getScalarValue(contextNode, "name")
+		contextType := configtypes.ConvertTargetToContextType(configtypes.Target(getScalarValue(contextNode, "target")))
+		if contextType == "" {
+			issues = append(issues, DoctorIssue{
+				Type:    DoctorIssueMissingContextType,
+				Message: fmt.Sprintf("context %q has no context type nor target", name),
+			})
+			continue
+		}
+		issues = append(issues, DoctorIssue{
+			Type:    DoctorIssueMissingContextType,
+			Message: fmt.Sprintf("context %q has no context type, expected %q", name, contextType),
+			Fixed:   fix,
+		})
+		if fix {
+			setScalarValue(contextNode, "contextType", string(contextType))
+		}
+	}
+	return issues, nil
+}
+
+func checkServersWithoutContext(node *yaml.Node, fix bool) ([]DoctorIssue, error) {
+	cfg, err := convertNodeToClientConfig(node)
+	if err != nil {
+		return nil, err
+	}
+	var issues []DoctorIssue
+	for _, s := range cfg.KnownServers {
+		if cfg.HasContext(s.Name) {
+			continue
+		}
+		issues = append(issues, DoctorIssue{
+			Type:    DoctorIssueServerWithoutContext,
+			Message: fmt.Sprintf("server %q has no matching context", s.Name),
+			Fixed:   fix,
+		})
+		if !fix {
+			continue
+		}
+		c := convertServerToContext(s)
+		if _, err := setContext(node, c); err != nil {
+			return nil, err
+		}
+		// The conflicts with the current contexts of other types are checked afterwards
+		if s.Name == cfg.CurrentServer && cfg.CurrentContext[c.ContextType] == "" {
+			currentContextNode := nodeutils.FindNode(node.Content[0], nodeutils.WithForceCreate(), nodeutils.WithKeys([]nodeutils.Key{{Name: KeyCurrentContext, Type: yaml.MappingNode}}))
+			setScalarValue(currentContextNode, string(c.ContextType), c.Name)
+		}
+	}
+	return issues, nil
+}
+
+func checkDanglingCurrentContexts(node *yaml.Node, fix bool) ([]DoctorIssue, error) {
+	cfg, err := convertNodeToClientConfig(node)
+	if err != nil {
+		return nil, err
+	}
+	var issues []DoctorIssue
+	for _, contextType := range getCurrentContextTypes(node) {
+		name := cfg.CurrentContext[contextType]
+		ctx, err := cfg.GetContext(name)
+		switch {
+		case err != nil:
+			issues = append(issues, DoctorIssue{
+				Type:    DoctorIssueDanglingCurrentContext,
+				Message: fmt.Sprintf("current context of type %q refers to missing context %q", contextType, name),
+				Fixed:   fix,
+			})
+		case ctx.ContextType != contextType:
+			issues = append(issues, DoctorIssue{
+				Type:    DoctorIssueDanglingCurrentContext,
+				Message: fmt.Sprintf("current context of type %q refers to context %q of type %q", contextType, name, ctx.ContextType),
+				Fixed:   fix,
+			})
+		default:
+			continue
+		}
+		if fix {
+			if err := removeCurrentContext(node, name, contextType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return issues, nil
+}
+
+func checkDanglingCurrentServer(node *yaml.Node, fix bool) ([]DoctorIssue, error) {
+	cfg, err := convertNodeToClientConfig(node)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.CurrentServer == "" || cfg.HasServer(cfg.CurrentServer) {
+		return nil, nil
+	}
+	issue := DoctorIssue{
+		Type:    DoctorIssueDanglingCurrentServer,
+		Message: fmt.Sprintf("current server refers to missing server %q", cfg.CurrentServer),
+		Fixed:   fix,
+	}
+	if fix {
+		if err := removeCurrentServer(node, cfg.CurrentServer); err != nil {
+			return nil, err
+		}
+	}
+	return []DoctorIssue{issue}, nil
+}
+
+// checkMutuallyExclusiveCurrentContexts reports the current contexts set along with the first current context
+// of the context types except TMC. The issues are not repaired: the config does not record which current context
+// was set last, which is the one SetCurrentContext keeps, so the user has to set the current context again.
+func checkMutuallyExclusiveCurrentContexts(node *yaml.Node, _ bool) ([]DoctorIssue, error) {
+	cfg, err := convertNodeToClientConfig(node)
+	if err != nil {
+		return nil, err
+	}
+	var active configtypes.ContextType
+	var issues []DoctorIssue
+	for _, contextType := range getCurrentContextTypes(node) {
+		if contextType == configtypes.ContextTypeTMC {
+			continue
+		}
+		if active == "" {
+			active = contextType
+			continue
+		}
+		issues = append(issues, DoctorIssue{
+			Type: DoctorIssueMutuallyExclusiveCurrentContexts,
+			Message: fmt.Sprintf("current context %q of type %q is set along with current context %q of type %q, set the current context to use again",
+				cfg.CurrentContext[contextType], contextType, cfg.CurrentContext[active], active),
+		})
+	}
+	return issues, nil
+}
+
+// getCurrentContextTypes returns the context types of the current contexts in the order of the config
+func getCurrentContextTypes(node *yaml.Node) []configtypes.ContextType {
+	currentContextNode := nodeutils.FindNode(node.Content[0], nodeutils.WithKeys([]nodeutils.Key{{Name: KeyCurrentContext}}))
+	if currentContextNode == nil {
+		return nil
+	}
+	var contextTypes []configtypes.ContextType
+	for i := 0; i+1 < len(currentContextNode.Content); i += 2 {
+		contextTypes = append(contextTypes, configtypes.ContextType(currentContextNode.Content[i].Value))
+	}
+	return contextTypes
+}
+
+// getScalarValue returns the value of the key of the mapping node, empty if not found
+func getScalarValue(node *yaml.Node, key string) string {
+	if index := nodeutils.GetNodeIndex(node.Content, key); index != -1 {
+		return node.Content[index].Value
+	}
+	return ""
+}
+
+// setScalarValue adds or updates the value of the key of the mapping node
+func setScalarValue(node *yaml.Node, key, value string) {
+	if index := nodeutils.GetNodeIndex(node.Content, key); index != -1 {
+		node.Content[index].Kind = yaml.ScalarNode
+		node.Content[index].Tag = nodeutils.NodeTagStr
+		node.Content[index].Value = value
+		return
+	}
+	node.Content = append(node.Content, nodeutils.CreateScalarNode(key, value)...)
+}
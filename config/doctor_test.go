@@ -0,0 +1,209 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	configtypes "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestDoctor(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        string
+		cfgNextGen string
+		issues     []DoctorIssue
+		unfixed    int
+		verify     func(t *testing.T)
+	}{
+		{
+			name: "no issues",
+			cfgNextGen: `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+currentContext:
+  kubernetes: prod
+`,
+		},
+		{
+			name: "duplicate context",
+			cfgNextGen: `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: other-ctx
+`,
+			issues: []DoctorIssue{
+				{Type: DoctorIssueDuplicateContext, Message: `context "prod" is defined more than once`},
+			},
+			verify: func(t *testing.T) {
+				cfg, err := GetClientConfig()
+				require.NoError(t, err)
+				require.Len(t, cfg.KnownContexts, 1)
+				assert.Equal(t, "prod-ctx", cfg.KnownContexts[0].ClusterOpts.Context)
+			},
+		},
+		{
+			name: "missing context type",
+			cfgNextGen: `contexts:
+  - name: prod
+    target: mission-control
+    globalOpts:
+      endpoint: test-endpoint
+  - name: dev
+`,
+			issues: []DoctorIssue{
+				{Type: DoctorIssueMissingContextType, Message: `context "prod" has no context type, expected "mission-control"`},
+				{Type: DoctorIssueMissingContextType, Message: `context "dev" has no context type nor target`},
+			},
+			unfixed: 1,
+			verify: func(t *testing.T) {
+				node, err := getClientConfigNode()
+				require.NoError(t, err)
+				contexts := node.Content[0].Content[1]
+				assert.Equal(t, string(configtypes.ContextTypeTMC), getScalarValue(contexts.Content[0], "contextType"))
+				assert.Equal(t, "", getScalarValue(contexts.Content[1], "contextType"))
+			},
+		},
+		{
+			name: "server without context",
+			cfg: `servers:
+  - name: prod
+    type: managementcluster
+    managementClusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+current: prod
+`,
+			issues: []DoctorIssue{
+				{Type: DoctorIssueServerWithoutContext, Message: `server "prod" has no matching context`},
+			},
+			verify: func(t *testing.T) {
+				ctx, err := GetContext("prod")
+				require.NoError(t, err)
+				assert.Equal(t, configtypes.ContextTypeK8s, ctx.ContextType)
+				assert.Equal(t, "prod-ctx", ctx.ClusterOpts.Context)
+
+				ctx, err = GetActiveContext(configtypes.ContextTypeK8s)
+				require.NoError(t, err)
+				assert.Equal(t, "prod", ctx.Name)
+			},
+		},
+		{
+			name: "dangling current context",
+			cfgNextGen: `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+currentContext:
+  mission-control: prod
+  kubernetes: dev
+`,
+			issues: []DoctorIssue{
+				{Type: DoctorIssueDanglingCurrentContext, Message: `current context of type "mission-control" refers to context "prod" of type "kubernetes"`},
+				{Type: DoctorIssueDanglingCurrentContext, Message: `current context of type "kubernetes" refers to missing context "dev"`},
+			},
+			verify: func(t *testing.T) {
+				activeContexts, err := GetAllActiveContextsMap()
+				require.NoError(t, err)
+				assert.Empty(t, activeContexts)
+			},
+		},
+		{
+			name: "dangling current server",
+			cfg: `current: prod
+`,
+			issues: []DoctorIssue{
+				{Type: DoctorIssueDanglingCurrentServer, Message: `current server refers to missing server "prod"`},
+			},
+			verify: func(t *testing.T) {
+				cfg, err := GetClientConfig()
+				require.NoError(t, err)
+				assert.Equal(t, "", cfg.CurrentServer)
+			},
+		},
+		{
+			name: "mutually exclusive current contexts",
+			cfgNextGen: `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+  - name: tap
+    target: kubernetes
+    contextType: tanzu
+    clusterOpts:
+      context: tap-ctx
+  - name: mc
+    target: mission-control
+    contextType: mission-control
+    globalOpts:
+      endpoint: test-endpoint
+currentContext:
+  kubernetes: prod
+  tanzu: tap
+  mission-control: mc
+`,
+			issues: []DoctorIssue{
+				{Type: DoctorIssueMutuallyExclusiveCurrentContexts, Message: `current context "tap" of type "tanzu" is set along with current context "prod" of type "kubernetes", set the current context to use again`},
+			},
+			// The current context to keep is not known, so the issue is not repaired
+			unfixed: 1,
+			verify: func(t *testing.T) {
+				activeContexts, err := GetAllActiveContextsMap()
+				require.NoError(t, err)
+				require.Len(t, activeContexts, 3)
+				assert.Equal(t, "prod", activeContexts[configtypes.ContextTypeK8s].Name)
+				assert.Equal(t, "tap", activeContexts[configtypes.ContextTypeTanzu].Name)
+			},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			_, cleanup := setupTestConfig(t, &CfgTestData{cfg: spec.cfg, cfgNextGen: spec.cfgNextGen})
+			defer cleanup()
+
+			// Without the fix option the issues are reported and the config is unchanged
+			report, err := Doctor()
+			require.NoError(t, err)
+			assert.Equal(t, spec.issues, report.Issues)
+			assert.Equal(t, len(spec.issues) > 0, report.HasIssues())
+			assert.Len(t, report.UnfixedIssues(), len(spec.issues))
+
+			report, err = Doctor()
+			require.NoError(t, err)
+			assert.Equal(t, spec.issues, report.Issues)
+
+			// With the fix option the issues are repaired
+			report, err = Doctor(WithDoctorFix())
+			require.NoError(t, err)
+			require.Len(t, report.Issues, len(spec.issues))
+			assert.Len(t, report.UnfixedIssues(), spec.unfixed)
+			if spec.verify != nil {
+				spec.verify(t)
+			}
+
+			report, err = Doctor()
+			require.NoError(t, err)
+			assert.Len(t, report.Issues, spec.unfixed)
+		})
+	}
+}
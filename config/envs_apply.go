@@ -0,0 +1,159 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Reasons for skipping an env configuration reported by ApplyEnvConfigurations
+const (
+	// EnvSkipReasonExported indicates the variable is already exported in the process environment
+	EnvSkipReasonExported = "already exported in the process environment"
+)
+
+// EnvApplyOptions is a struct that defines the options for applying the env configurations.
+type EnvApplyOptions struct {
+	Override bool // Override indicates whether to override the variables already exported in the process environment.
+	DryRun   bool // DryRun indicates whether to only report the changes without setting the process environment.
+}
+
+// EnvApplyOption is a function type that applies configuration options to EnvApplyOptions.
+type EnvApplyOption func(opts *EnvApplyOptions)
+
+// WithEnvOverride returns an EnvApplyOption function that sets the Override option to true.
+func WithEnvOverride() EnvApplyOption {
+	return func(opts *EnvApplyOptions) {
+		opts.Override = true
+	}
+}
+
+// WithEnvDryRun returns an EnvApplyOption function that sets the DryRun option to true.
+func WithEnvDryRun() EnvApplyOption {
+	return func(opts *EnvApplyOptions) {
+		opts.DryRun = true
+	}
+}
+
+// EnvApplyReport reports the result of applying the env configurations to the process environment
+type EnvApplyReport struct {
+	// Applied contains the variables set from the tanzu configuration mapped to their expanded values
+	Applied map[string]string
+	// Skipped contains the variables that were not set mapped to the reason
+	Skipped map[string]string
+}
+
+// ApplyEnvConfigurations sets the env configurations of the tanzu configuration (see GetEnvConfigurations)
+// in the process environment and returns a report of the variables applied and skipped.
+//
+// References to other variables in the values of the form ${VAR} or $VAR are expanded. References to
+// variables configured as part of the tanzu configuration are resolved to their (expanded) configured
+// value, other references are resolved from the process environment. Variables with cyclic references
+// are skipped.
+// Variables already exported in the process environment are not overridden (and references to them
+// resolve to the exported value) unless the WithEnvOverride option is specified.
+func ApplyEnvConfigurations(opts ...EnvApplyOption) (*EnvApplyReport, error) {
+	options := new(EnvApplyOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	configEnvs := GetEnvConfigurations()
+	resolver := newEnvResolver(configEnvs, options.Override)
+
+	report := &EnvApplyReport{
+		Applied: make(map[string]string),
+		Skipped: make(map[string]string),
+	}
+
+	keys := make([]string, 0, len(configEnvs))
+	for key := range configEnvs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if resolver.exported[key] && !options.Override {
+			report.Skipped[key] = EnvSkipReasonExported
+			continue
+		}
+		value, err := resolver.resolve(key)
+		if err != nil {
+			report.Skipped[key] = err.Error()
+			continue
+		}
+		if !options.DryRun {
+			if err := os.Setenv(key, value); err != nil {
+				return report, errors.Wrapf(err, "failed to set the environment variable %q", key)
+			}
+		}
+		report.Applied[key] = value
+	}
+	return report, nil
+}
+
+// envResolver expands the references in the values of the env configurations
+type envResolver struct {
+	configEnvs map[string]string
+	override   bool
+	// exported contains the config variables exported in the process environment before applying
+	exported map[string]bool
+	resolved map[string]string
+	// resolving is the chain of variables being resolved, used to detect cycles
+	resolving []string
+}
+
+func newEnvResolver(configEnvs map[string]string, override bool) *envResolver {
+	exported := make(map[string]bool)
+	for key := range configEnvs {
+		if _, ok := os.LookupEnv(key); ok {
+			exported[key] = true
+		}
+	}
+	return &envResolver{
+		configEnvs: configEnvs,
+		override:   override,
+		exported:   exported,
+		resolved:   make(map[string]string),
+	}
+}
+
+// resolve returns the expanded value of the config variable
+func (r *envResolver) resolve(key string) (string, error) {
+	if value, ok := r.resolved[key]; ok {
+		return value, nil
+	}
+	for i, k := range r.resolving {
+		if k == key {
+			cycle := append(append([]string{}, r.resolving[i:]...), key)
+			return "", errors.Errorf("cyclic reference detected: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	r.resolving = append(r.resolving, key)
+	defer func() {
+		r.resolving = r.resolving[:len(r.resolving)-1]
+	}()
+
+	var resolveErr error
+	value := os.Expand(r.configEnvs[key], func(name string) string {
+		if _, ok := r.configEnvs[name]; !ok || (r.exported[name] && !r.override) {
+			return os.Getenv(name)
+		}
+		v, err := r.resolve(name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+		return v
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	r.resolved[key] = value
+	return value, nil
+}
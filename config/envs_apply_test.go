@@ -0,0 +1,146 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyEnvConfigurations(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		exported map[string]string
+		opts     []EnvApplyOption
+		applied  map[string]string
+		skipped  map[string]string
+		environ  map[string]string
+	}{
+		{
+			name: "should expand references to the process environment and other configured variables",
+			config: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "${TEST_APPLY_HOME}/certs",
+				"TEST_APPLY_CA_FILE":   "$TEST_APPLY_CERTS_DIR/ca.crt",
+				"TEST_APPLY_PLAIN":     "plain",
+			},
+			exported: map[string]string{
+				"TEST_APPLY_HOME": "/home/user",
+			},
+			applied: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/home/user/certs",
+				"TEST_APPLY_CA_FILE":   "/home/user/certs/ca.crt",
+				"TEST_APPLY_PLAIN":     "plain",
+			},
+			skipped: map[string]string{},
+			environ: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/home/user/certs",
+				"TEST_APPLY_CA_FILE":   "/home/user/certs/ca.crt",
+				"TEST_APPLY_PLAIN":     "plain",
+			},
+		},
+		{
+			name: "should not override exported variables and resolve references to the exported value",
+			config: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/config/certs",
+				"TEST_APPLY_CA_FILE":   "${TEST_APPLY_CERTS_DIR}/ca.crt",
+			},
+			exported: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/exported/certs",
+			},
+			applied: map[string]string{
+				"TEST_APPLY_CA_FILE": "/exported/certs/ca.crt",
+			},
+			skipped: map[string]string{
+				"TEST_APPLY_CERTS_DIR": EnvSkipReasonExported,
+			},
+			environ: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/exported/certs",
+				"TEST_APPLY_CA_FILE":   "/exported/certs/ca.crt",
+			},
+		},
+		{
+			name: "should override exported variables with the override option",
+			config: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/config/certs",
+				"TEST_APPLY_CA_FILE":   "${TEST_APPLY_CERTS_DIR}/ca.crt",
+			},
+			exported: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/exported/certs",
+			},
+			opts: []EnvApplyOption{WithEnvOverride()},
+			applied: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/config/certs",
+				"TEST_APPLY_CA_FILE":   "/config/certs/ca.crt",
+			},
+			skipped: map[string]string{},
+			environ: map[string]string{
+				"TEST_APPLY_CERTS_DIR": "/config/certs",
+				"TEST_APPLY_CA_FILE":   "/config/certs/ca.crt",
+			},
+		},
+		{
+			name: "should skip variables with cyclic references",
+			config: map[string]string{
+				"TEST_APPLY_A":     "${TEST_APPLY_B}",
+				"TEST_APPLY_B":     "${TEST_APPLY_A}",
+				"TEST_APPLY_SELF":  "x${TEST_APPLY_SELF}",
+				"TEST_APPLY_PLAIN": "plain",
+			},
+			applied: map[string]string{
+				"TEST_APPLY_PLAIN": "plain",
+			},
+			skipped: map[string]string{
+				"TEST_APPLY_A":    "cyclic reference detected: TEST_APPLY_A -> TEST_APPLY_B -> TEST_APPLY_A",
+				"TEST_APPLY_B":    "cyclic reference detected: TEST_APPLY_B -> TEST_APPLY_A -> TEST_APPLY_B",
+				"TEST_APPLY_SELF": "cyclic reference detected: TEST_APPLY_SELF -> TEST_APPLY_SELF",
+			},
+			environ: map[string]string{
+				"TEST_APPLY_PLAIN": "plain",
+			},
+		},
+		{
+			name: "should not set the process environment with the dry run option",
+			config: map[string]string{
+				"TEST_APPLY_PLAIN": "plain",
+			},
+			opts: []EnvApplyOption{WithEnvDryRun()},
+			applied: map[string]string{
+				"TEST_APPLY_PLAIN": "plain",
+			},
+			skipped: map[string]string{},
+			environ: map[string]string{},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			// Setup config test data
+			_, cleanUp := setupTestConfig(t, &CfgTestData{})
+			defer cleanUp()
+
+			for key, value := range spec.config {
+				err := SetEnv(key, value)
+				assert.NoError(t, err)
+				defer os.Unsetenv(key)
+			}
+			for key, value := range spec.exported {
+				t.Setenv(key, value)
+			}
+
+			report, err := ApplyEnvConfigurations(spec.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, spec.applied, report.Applied)
+			assert.Equal(t, spec.skipped, report.Skipped)
+
+			for key := range spec.config {
+				value, ok := os.LookupEnv(key)
+				expected, expectedOk := spec.environ[key]
+				assert.Equal(t, expectedOk, ok, key)
+				assert.Equal(t, expected, value, key)
+			}
+		})
+	}
+}
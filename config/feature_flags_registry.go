@@ -0,0 +1,184 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// StaleFeatureFlagReason is the reason a feature flag set in the config is considered stale
+type StaleFeatureFlagReason string
+
+const (
+	// StaleFeatureFlagUnknown indicates the feature flag is not declared by the plugin
+	StaleFeatureFlagUnknown StaleFeatureFlagReason = "unknown"
+	// StaleFeatureFlagExpired indicates the expiry date of the feature flag has passed
+	StaleFeatureFlagExpired StaleFeatureFlagReason = "expired"
+)
+
+// StaleFeatureFlag is a feature flag set in the config which is either unknown or expired
+type StaleFeatureFlag struct {
+	Plugin string
+	Name   string
+	Value  string
+	Reason StaleFeatureFlagReason
+}
+
+// featureFlagRegistry holds the feature flags declared by the plugins keyed by plugin and flag name
+var featureFlagRegistry = struct {
+	sync.RWMutex
+	specs map[string]map[string]types.FeatureFlagSpec
+}{specs: make(map[string]map[string]types.FeatureFlagSpec)}
+
+// timeNow returns the current time, overridden in tests
+var timeNow = time.Now
+
+// RegisterFeatureFlags declares the feature flags of the plugin. Declaring a flag with the name
+// of an already declared flag replaces the previous declaration.
+func RegisterFeatureFlags(plugin string, specs ...types.FeatureFlagSpec) error {
+	if plugin == "" {
+		return errors.New("plugin cannot be empty")
+	}
+	for i := range specs {
+		if err := specs[i].Validate(); err != nil {
+			return errors.Wrapf(err, "invalid feature flag declaration for plugin %q", plugin)
+		}
+	}
+
+	featureFlagRegistry.Lock()
+	defer featureFlagRegistry.Unlock()
+	if featureFlagRegistry.specs[plugin] == nil {
+		featureFlagRegistry.specs[plugin] = make(map[string]types.FeatureFlagSpec)
+	}
+	for _, spec := range specs {
+		featureFlagRegistry.specs[plugin][spec.Name] = spec
+	}
+	return nil
+}
+
+// GetRegisteredFeatureFlags returns the feature flags declared by the plugin sorted by name
+func GetRegisteredFeatureFlags(plugin string) []types.FeatureFlagSpec {
+	featureFlagRegistry.RLock()
+	defer featureFlagRegistry.RUnlock()
+	specs := make([]types.FeatureFlagSpec, 0, len(featureFlagRegistry.specs[plugin]))
+	for _, spec := range featureFlagRegistry.specs[plugin] {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		return specs[i].Name < specs[j].Name
+	})
+	return specs
+}
+
+func getRegisteredFeatureFlag(plugin, key string) (types.FeatureFlagSpec, bool) {
+	featureFlagRegistry.RLock()
+	defer featureFlagRegistry.RUnlock()
+	spec, ok := featureFlagRegistry.specs[plugin][key]
+	return spec, ok
+}
+
+// GetFeatureBool returns the value of the declared bool feature flag of the plugin.
+// The declared default is returned if the flag is not set in the config.
+func GetFeatureBool(plugin, key string) (bool, error) {
+	val, err := getTypedFeature(plugin, key, types.FeatureFlagTypeBool)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(val)
+}
+
+// GetFeatureInt returns the value of the declared int feature flag of the plugin.
+// The declared default is returned if the flag is not set in the config.
+func GetFeatureInt(plugin, key string) (int, error) {
+	val, err := getTypedFeature(plugin, key, types.FeatureFlagTypeInt)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(val)
+}
+
+// GetFeatureDuration returns the value of the declared duration feature flag of the plugin.
+// The declared default is returned if the flag is not set in the config.
+func GetFeatureDuration(plugin, key string) (time.Duration, error) {
+	val, err := getTypedFeature(plugin, key, types.FeatureFlagTypeDuration)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(val)
+}
+
+// GetFeatureString returns the value of the declared string feature flag of the plugin.
+// The declared default is returned if the flag is not set in the config.
+func GetFeatureString(plugin, key string) (string, error) {
+	return getTypedFeature(plugin, key, types.FeatureFlagTypeString)
+}
+
+// getTypedFeature returns the value of the feature flag after validating it against the declaration.
+// A feature flag override (see types.GetFeatureOverride) takes precedence over the value in the config.
+func getTypedFeature(plugin, key string, flagType types.FeatureFlagType) (string, error) {
+	spec, ok := getRegisteredFeatureFlag(plugin, key)
+	if !ok {
+		return "", errors.Errorf("feature flag %q is not declared by plugin %q", key, plugin)
+	}
+	if spec.GetType() != flagType {
+		return "", errors.Errorf("feature flag %q of plugin %q is of type %q, not %q", key, plugin, spec.GetType(), flagType)
+	}
+
+	val, err := getFeatureWithOverride(plugin, key)
+	if err != nil {
+		return spec.GetDefault(), nil
+	}
+	if err := spec.ValidateValue(val); err != nil {
+		return "", errors.Wrapf(err, "invalid value configured for plugin %q", plugin)
+	}
+	return val, nil
+}
+
+// GetStaleFeatureFlags returns the feature flags set in the config which are either not declared
+// or expired, sorted by plugin and name. Only the feature flags of plugins which have
+// registered declarations are considered.
+func GetStaleFeatureFlags() ([]StaleFeatureFlag, error) {
+	cfg, err := GetClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ClientOptions == nil || cfg.ClientOptions.Features == nil {
+		return nil, nil
+	}
+
+	now := timeNow()
+	var stale []StaleFeatureFlag
+
+	featureFlagRegistry.RLock()
+	defer featureFlagRegistry.RUnlock()
+	for plugin, features := range cfg.ClientOptions.Features {
+		specs, ok := featureFlagRegistry.specs[plugin]
+		if !ok {
+			continue
+		}
+		for key, value := range features {
+			spec, declared := specs[key]
+			switch {
+			case !declared:
+				stale = append(stale, StaleFeatureFlag{Plugin: plugin, Name: key, Value: value, Reason: StaleFeatureFlagUnknown})
+			case spec.IsExpired(now):
+				stale = append(stale, StaleFeatureFlag{Plugin: plugin, Name: key, Value: value, Reason: StaleFeatureFlagExpired})
+			}
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Plugin == stale[j].Plugin {
+			return stale[i].Name < stale[j].Name
+		}
+		return stale[i].Plugin < stale[j].Plugin
+	})
+	return stale, nil
+}
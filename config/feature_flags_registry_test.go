@@ -0,0 +1,114 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestTypedFeatureFlags(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	err := RegisterFeatureFlags("typed-plugin",
+		types.FeatureFlagSpec{Name: "enabled", Default: "true"},
+		types.FeatureFlagSpec{Name: "retries", Type: types.FeatureFlagTypeInt, Default: "3"},
+		types.FeatureFlagSpec{Name: "timeout", Type: types.FeatureFlagTypeDuration},
+		types.FeatureFlagSpec{Name: "output", Type: types.FeatureFlagTypeString, Default: "json", AllowedValues: []string{"json", "yaml"}},
+	)
+	assert.NoError(t, err)
+
+	err = RegisterFeatureFlags("typed-plugin", types.FeatureFlagSpec{Name: "bad", Type: "float"})
+	assert.ErrorContains(t, err, "invalid feature flag declaration for plugin \"typed-plugin\"")
+
+	// Declared defaults are returned when the flags are not set
+	enabled, err := GetFeatureBool("typed-plugin", "enabled")
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	retries, err := GetFeatureInt("typed-plugin", "retries")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, retries)
+	timeout, err := GetFeatureDuration("typed-plugin", "timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+	output, err := GetFeatureString("typed-plugin", "output")
+	assert.NoError(t, err)
+	assert.Equal(t, "json", output)
+
+	// Configured values are returned when set
+	assert.NoError(t, SetFeature("typed-plugin", "enabled", "false"))
+	assert.NoError(t, SetFeature("typed-plugin", "retries", "5"))
+	assert.NoError(t, SetFeature("typed-plugin", "timeout", "1m30s"))
+	assert.NoError(t, SetFeature("typed-plugin", "output", "yaml"))
+
+	enabled, err = GetFeatureBool("typed-plugin", "enabled")
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	retries, err = GetFeatureInt("typed-plugin", "retries")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, retries)
+	timeout, err = GetFeatureDuration("typed-plugin", "timeout")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+	output, err = GetFeatureString("typed-plugin", "output")
+	assert.NoError(t, err)
+	assert.Equal(t, "yaml", output)
+
+	// Configured values are validated against the declaration
+	assert.NoError(t, SetFeature("typed-plugin", "retries", "many"))
+	_, err = GetFeatureInt("typed-plugin", "retries")
+	assert.ErrorContains(t, err, "invalid value configured for plugin \"typed-plugin\": feature flag \"retries\": value \"many\" is not a valid int")
+	assert.NoError(t, SetFeature("typed-plugin", "output", "xml"))
+	_, err = GetFeatureString("typed-plugin", "output")
+	assert.ErrorContains(t, err, "value \"xml\" is not one of the allowed values [json, yaml]")
+
+	// Getters validate the declaration
+	_, err = GetFeatureInt("typed-plugin", "enabled")
+	assert.ErrorContains(t, err, "feature flag \"enabled\" of plugin \"typed-plugin\" is of type \"bool\", not \"int\"")
+	_, err = GetFeatureBool("typed-plugin", "missing")
+	assert.ErrorContains(t, err, "feature flag \"missing\" is not declared by plugin \"typed-plugin\"")
+}
+
+func TestGetStaleFeatureFlags(t *testing.T) {
+	// Setup config test data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+		timeNow = time.Now
+	}()
+	timeNow = func() time.Time {
+		return time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	}
+
+	err := RegisterFeatureFlags("stale-plugin",
+		types.FeatureFlagSpec{Name: "current", Expiry: "2024-12-31"},
+		types.FeatureFlagSpec{Name: "expired", Expiry: "2024-06-30"},
+		types.FeatureFlagSpec{Name: "permanent"},
+	)
+	assert.NoError(t, err)
+
+	assert.NoError(t, SetFeature("stale-plugin", "current", "true"))
+	assert.NoError(t, SetFeature("stale-plugin", "expired", "true"))
+	assert.NoError(t, SetFeature("stale-plugin", "permanent", "true"))
+	assert.NoError(t, SetFeature("stale-plugin", "removed", "false"))
+	// Feature flags of plugins without declarations are not considered
+	assert.NoError(t, SetFeature("undeclared-plugin", "flag", "true"))
+
+	stale, err := GetStaleFeatureFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []StaleFeatureFlag{
+		{Plugin: "stale-plugin", Name: "expired", Value: "true", Reason: StaleFeatureFlagExpired},
+		{Plugin: "stale-plugin", Name: "removed", Value: "false", Reason: StaleFeatureFlagUnknown},
+	}, stale)
+}
@@ -27,13 +27,9 @@ func GetAllFeatureFlags() (map[string]types.FeatureMap, error) {
 }
 
 // IsFeatureEnabled checks and returns whether specific plugin and key is true
+// A feature flag override (see types.GetFeatureOverride) takes precedence over the value in the config
 func IsFeatureEnabled(plugin, key string) (bool, error) {
-	// Retrieve client config node
-	node, err := getClientConfigNode()
-	if err != nil {
-		return false, err
-	}
-	val, err := getFeature(node, plugin, key)
+	val, err := getFeatureWithOverride(plugin, key)
 	if err != nil {
 		return false, err
 	}
@@ -43,6 +39,20 @@ func IsFeatureEnabled(plugin, key string) (bool, error) {
 	return false, nil
 }
 
+// getFeatureWithOverride returns the overridden value of the feature flag if overridden, otherwise
+// the value in the config
+func getFeatureWithOverride(plugin, key string) (string, error) {
+	if val, ok := types.GetFeatureOverride(plugin, key); ok {
+		return val, nil
+	}
+	// Retrieve client config node
+	node, err := getClientConfigNode()
+	if err != nil {
+		return "", err
+	}
+	return getFeature(node, plugin, key)
+}
+
 func getFeature(node *yaml.Node, plugin, key string) (string, error) {
 	// check if plugin is empty
 	if plugin == "" {
@@ -206,10 +216,12 @@ func ConfigureDefaultFeatureFlagsIfMissing(plugin string, defaultFeatureFlags ma
 
 // IsFeatureActivated returns true if the given feature is activated
 // User can set this CLI feature flag using `tanzu config set features.global.<feature> true`
+// A feature flag override (see types.GetFeatureOverride) takes precedence over the value in the config
 func IsFeatureActivated(feature string) bool {
 	cfg, err := GetClientConfig()
 	if err != nil {
-		return false
+		// Feature flag overrides are honored even if the config cannot be read
+		cfg = &types.ClientConfig{}
 	}
 	status, err := cfg.IsConfigFeatureActivated(feature)
 	if err != nil {
@@ -358,3 +358,46 @@ func TestGetAllFeatureFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestFeatureOverrides(t *testing.T) {
+	// Setup config data
+	_, cleanUp := setupTestConfig(t, &CfgTestData{})
+
+	defer func() {
+		cleanUp()
+		configtypes.ResetFeatureOverrides()
+	}()
+
+	err := SetFeature("override-plugin", "feature1", "false")
+	assert.NoError(t, err)
+
+	enabled, err := IsFeatureEnabled("override-plugin", "feature1")
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	assert.False(t, IsFeatureActivated("features.override-plugin.feature1"))
+
+	// Environment variable override takes precedence over the config
+	t.Setenv("TANZU_FEATURE_OVERRIDE_PLUGIN_FEATURE1", "true")
+	enabled, err = IsFeatureEnabled("override-plugin", "feature1")
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	assert.True(t, IsFeatureActivated("features.override-plugin.feature1"))
+
+	// Override for the current process takes precedence over the environment variable
+	configtypes.SetFeatureOverride("override-plugin", "feature1", "false")
+	enabled, err = IsFeatureEnabled("override-plugin", "feature1")
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+	assert.False(t, IsFeatureActivated("features.override-plugin.feature1"))
+
+	// Overrides of feature flags not set in the config
+	configtypes.SetFeatureOverride("override-plugin", "feature2", "true")
+	enabled, err = IsFeatureEnabled("override-plugin", "feature2")
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+
+	// Overrides are not persisted
+	cfg, err := GetClientConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, configtypes.FeatureMap{"feature1": "false"}, cfg.ClientOptions.Features["override-plugin"])
+}
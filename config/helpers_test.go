@@ -5,6 +5,7 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,11 +52,27 @@ func setupTestConfig(t *testing.T, data *CfgTestData) (files []*os.File, cleanup
 
 		err = os.Remove(cfgMetadataFile.Name())
 		assert.NoError(t, err)
+
+		for _, f := range []*os.File{cfgFile, cfgNextGenFile, cfgMetadataFile} {
+			err = os.RemoveAll(configSnapshotsDir(f.Name()))
+			assert.NoError(t, err)
+		}
 	}
 
 	return []*os.File{cfgFile, cfgNextGenFile, cfgMetadataFile}, cleanup
 }
 
+// setupLocalDiscoveryPaths creates the specified directories under a temp dir to be used as
+// local discovery source paths and returns the temp dir
+func setupLocalDiscoveryPaths(t *testing.T, dirs ...string) string {
+	localDir := t.TempDir()
+	for _, dir := range dirs {
+		err := os.MkdirAll(filepath.Join(localDir, dir), 0755)
+		assert.NoError(t, err)
+	}
+	return localDir
+}
+
 func setupConfigMetadataWithMigrateToNewConfig() string {
 	metadata := `configMetadata:
   settings:
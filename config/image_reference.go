@@ -0,0 +1,167 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultImageRegistry is the registry assumed for image references that do not specify a registry
+	DefaultImageRegistry = "docker.io"
+	// DefaultImageTag is the tag assumed for image references that specify neither a tag nor a digest
+	DefaultImageTag = "latest"
+
+	// defaultImageRepositoryPrefix is the prefix of the single component repositories of the default registry
+	defaultImageRepositoryPrefix = "library/"
+)
+
+var (
+	// imageDomainRegexp matches the registry part of the image reference, e.g. harbor.my-domain.local:5000
+	imageDomainRegexp = regexp.MustCompile(`^(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?$`)
+	// imagePathComponentRegexp matches a single path component of the repository
+	imagePathComponentRegexp = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*$`)
+	// imageTagRegexp matches the tag of the image reference
+	imageTagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+	// imageDigestRegexp matches the digest of the image reference
+	imageDigestRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+
+	// imageDigestLengths are the lengths of the encoded digests of the registered digest algorithms
+	imageDigestLengths = map[string]int{
+		"sha256": 64,
+		"sha512": 128,
+	}
+
+	// defaultImageRegistryAliases are the aliases of the default registry
+	defaultImageRegistryAliases = []string{"index.docker.io", "registry-1.docker.io"}
+)
+
+// ImageReference is the normalized representation of an OCI image reference
+type ImageReference struct {
+	// Registry is the registry host(host:port) of the image.
+	// E.g., harbor.my-domain.local:5000
+	Registry string
+	// Repository is the repository path of the image within the registry
+	// E.g., tanzu-cli/plugins/central
+	Repository string
+	// Tag of the image. Empty if the image is referenced by digest only
+	Tag string
+	// Digest of the image. Empty if not specified
+	Digest string
+}
+
+// ParseImageReference parses the image reference of the form [registry/]repository[:tag][@digest]
+// and returns an error if it is not well-formed.
+//
+// The returned reference is normalized:
+//   - the registry is lowercased and defaults to DefaultImageRegistry
+//   - single component repositories of the default registry are prefixed with "library/"
+//   - the tag defaults to DefaultImageTag if neither a tag nor a digest is specified
+//   - the encoded part of the digest is lowercased
+func ParseImageReference(image string) (*ImageReference, error) {
+	if image == "" {
+		return nil, errors.New("image reference cannot be empty")
+	}
+	if strings.TrimSpace(image) != image {
+		return nil, errors.Errorf("invalid image reference %q: must not contain leading or trailing spaces", image)
+	}
+
+	ref := &ImageReference{}
+	remainder := image
+
+	if idx := strings.Index(remainder, "@"); idx != -1 {
+		digest, err := normalizeImageDigest(remainder[idx+1:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid image reference %q", image)
+		}
+		ref.Digest = digest
+		remainder = remainder[:idx]
+	}
+
+	// The tag separator is the last ':' after the last '/', anything else is part of the registry port
+	if idx := strings.LastIndex(remainder, ":"); idx != -1 && idx > strings.LastIndex(remainder, "/") {
+		ref.Tag = remainder[idx+1:]
+		remainder = remainder[:idx]
+		if !imageTagRegexp.MatchString(ref.Tag) {
+			return nil, errors.Errorf("invalid image reference %q: invalid tag %q", image, ref.Tag)
+		}
+	}
+
+	// The first component is the registry if it looks like a host
+	if idx := strings.Index(remainder, "/"); idx != -1 {
+		first := remainder[:idx]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			ref.Registry = first
+			remainder = remainder[idx+1:]
+			if !imageDomainRegexp.MatchString(ref.Registry) {
+				return nil, errors.Errorf("invalid image reference %q: invalid registry %q", image, ref.Registry)
+			}
+		}
+	}
+
+	if remainder == "" {
+		return nil, errors.Errorf("invalid image reference %q: repository cannot be empty", image)
+	}
+	for _, component := range strings.Split(remainder, "/") {
+		if !imagePathComponentRegexp.MatchString(component) {
+			return nil, errors.Errorf("invalid image reference %q: invalid repository path component %q", image, component)
+		}
+	}
+	ref.Repository = remainder
+
+	ref.normalize()
+	return ref, nil
+}
+
+// normalize sets the defaults of the image reference
+func (r *ImageReference) normalize() {
+	r.Registry = strings.ToLower(r.Registry)
+	for _, alias := range defaultImageRegistryAliases {
+		if r.Registry == alias {
+			r.Registry = DefaultImageRegistry
+		}
+	}
+	if r.Registry == "" {
+		r.Registry = DefaultImageRegistry
+	}
+	if r.Registry == DefaultImageRegistry && !strings.Contains(r.Repository, "/") {
+		r.Repository = defaultImageRepositoryPrefix + r.Repository
+	}
+	if r.Tag == "" && r.Digest == "" {
+		r.Tag = DefaultImageTag
+	}
+}
+
+// Name returns the fully qualified repository of the image i.e. registry/repository
+func (r *ImageReference) Name() string {
+	return r.Registry + "/" + r.Repository
+}
+
+// String returns the fully qualified image reference i.e. registry/repository[:tag][@digest]
+func (r *ImageReference) String() string {
+	s := r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
+
+// normalizeImageDigest validates the digest of the form algorithm:encoded and returns it
+// with the encoded part lowercased
+func normalizeImageDigest(digest string) (string, error) {
+	if !imageDigestRegexp.MatchString(digest) {
+		return "", errors.Errorf("invalid digest %q", digest)
+	}
+	algorithm, encoded, _ := strings.Cut(digest, ":")
+	if length, ok := imageDigestLengths[algorithm]; ok && len(encoded) != length {
+		return "", errors.Errorf("invalid digest %q: %s digest must be %d characters long", digest, algorithm, length)
+	}
+	return algorithm + ":" + strings.ToLower(encoded), nil
+}
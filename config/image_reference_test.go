@@ -0,0 +1,108 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImageReference(t *testing.T) {
+	sha256Digest := "sha256:" + strings.Repeat("ab", 32)
+
+	tests := []struct {
+		name   string
+		image  string
+		out    *ImageReference
+		str    string
+		errStr string
+	}{
+		{
+			name:  "single component repository defaults to the default registry and tag",
+			image: "busybox",
+			out:   &ImageReference{Registry: "docker.io", Repository: "library/busybox", Tag: "latest"},
+			str:   "docker.io/library/busybox:latest",
+		},
+		{
+			name:  "multi component repository of the default registry",
+			image: "tanzu/plugins:v1",
+			out:   &ImageReference{Registry: "docker.io", Repository: "tanzu/plugins", Tag: "v1"},
+			str:   "docker.io/tanzu/plugins:v1",
+		},
+		{
+			name:  "default registry alias is normalized",
+			image: "index.docker.io/busybox:v1",
+			out:   &ImageReference{Registry: "docker.io", Repository: "library/busybox", Tag: "v1"},
+			str:   "docker.io/library/busybox:v1",
+		},
+		{
+			name:  "registry with port is lowercased",
+			image: "Harbor.My-Domain.local:5000/tanzu-cli/plugins/central:v1.0.0",
+			out:   &ImageReference{Registry: "harbor.my-domain.local:5000", Repository: "tanzu-cli/plugins/central", Tag: "v1.0.0"},
+			str:   "harbor.my-domain.local:5000/tanzu-cli/plugins/central:v1.0.0",
+		},
+		{
+			name:  "localhost registry",
+			image: "localhost/plugins",
+			out:   &ImageReference{Registry: "localhost", Repository: "plugins", Tag: "latest"},
+			str:   "localhost/plugins:latest",
+		},
+		{
+			name:  "digest only reference does not default the tag",
+			image: "localhost:5000/plugins@sha256:" + strings.Repeat("AB", 32),
+			out:   &ImageReference{Registry: "localhost:5000", Repository: "plugins", Digest: sha256Digest},
+			str:   "localhost:5000/plugins@" + sha256Digest,
+		},
+		{
+			name:  "tag and digest",
+			image: "localhost:5000/plugins:v1@" + sha256Digest,
+			out:   &ImageReference{Registry: "localhost:5000", Repository: "plugins", Tag: "v1", Digest: sha256Digest},
+			str:   "localhost:5000/plugins:v1@" + sha256Digest,
+		},
+		{
+			name:   "empty reference",
+			image:  "",
+			errStr: "image reference cannot be empty",
+		},
+		{
+			name:   "uppercase repository",
+			image:  "localhost/Plugins",
+			errStr: "invalid image reference \"localhost/Plugins\": invalid repository path component \"Plugins\"",
+		},
+		{
+			name:   "empty repository",
+			image:  "localhost:5000/:v1",
+			errStr: "invalid image reference \"localhost:5000/:v1\": repository cannot be empty",
+		},
+		{
+			name:   "invalid tag",
+			image:  "plugins:-v1",
+			errStr: "invalid image reference \"plugins:-v1\": invalid tag \"-v1\"",
+		},
+		{
+			name:   "invalid digest",
+			image:  "plugins@sha256:xyz",
+			errStr: "invalid image reference \"plugins@sha256:xyz\": invalid digest \"sha256:xyz\"",
+		},
+		{
+			name:   "digest of invalid length",
+			image:  "plugins@sha256:" + strings.Repeat("a", 40),
+			errStr: "sha256 digest must be 64 characters long",
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			ref, err := ParseImageReference(spec.image)
+			if spec.errStr != "" {
+				assert.ErrorContains(t, err, spec.errStr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, spec.out, ref)
+			assert.Equal(t, spec.str, ref.String())
+		})
+	}
+}
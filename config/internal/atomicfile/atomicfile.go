@@ -0,0 +1,75 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package atomicfile provides atomic writes of files so that readers never observe a partially written file
+package atomicfile
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// WriteFile writes the data to a temporary file in the directory of the file, syncs it to disk and
+// renames it over the file, so that the file either has its previous or its new content.
+// If the file is a symlink, the target of the symlink is replaced and the symlink is preserved.
+// Like os.WriteFile, the mode of an existing file is kept and perm (before umask) is only used for a new file.
+func WriteFile(filename string, data []byte, perm os.FileMode) (err error) {
+	if target, evalErr := filepath.EvalSymlinks(filename); evalErr == nil {
+		filename = target
+	}
+
+	// The mode of the existing file is restored explicitly as the umask applies to the temporary file
+	var existingMode os.FileMode
+	info, statErr := os.Stat(filename)
+	if statErr == nil {
+		existingMode = info.Mode().Perm()
+	}
+
+	tmp, err := createTempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-", perm)
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary file")
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return errors.Wrap(err, "failed to write temporary file")
+	}
+	if statErr == nil {
+		if err = tmp.Chmod(existingMode); err != nil {
+			return errors.Wrap(err, "failed to set permissions of temporary file")
+		}
+	}
+	if err = tmp.Sync(); err != nil {
+		return errors.Wrap(err, "failed to sync temporary file")
+	}
+	if err = tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to close temporary file")
+	}
+	if err = os.Rename(tmp.Name(), filename); err != nil {
+		return errors.Wrapf(err, "failed to rename temporary file to %q", filename)
+	}
+	return nil
+}
+
+// createTempFile creates a new file with a random name starting with prefix in the directory.
+// Unlike os.CreateTemp, the file is created with perm (before umask) instead of 0600.
+func createTempFile(dir, prefix string, perm os.FileMode) (*os.File, error) {
+	for i := 0; i < 10000; i++ {
+		name := filepath.Join(dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10)) //nolint:gosec
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) {
+			continue
+		}
+		return f, err
+	}
+	return nil, errors.Errorf("failed to find a unique name for the temporary file in %q", dir)
+}
@@ -0,0 +1,92 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yaml")
+
+	err := WriteFile(filename, []byte("first"), 0o600)
+	assert.NoError(t, err)
+	data, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	err = WriteFile(filename, []byte("second"), 0o600)
+	assert.NoError(t, err)
+	data, err = os.ReadFile(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filename)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	// No temporary files should be left behind
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWriteFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "config.yaml")
+
+	// The mode of the existing file is kept
+	assert.NoError(t, os.WriteFile(filename, []byte("old"), 0o600))
+	err := WriteFile(filename, []byte("new"), 0o644)
+	assert.NoError(t, err)
+	info, err := os.Stat(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A new file is created with the specified permissions, subject to the umask
+	newFilename := filepath.Join(dir, "new.yaml")
+	err = WriteFile(newFilename, []byte("new"), 0o640)
+	assert.NoError(t, err)
+	info, err = os.Stat(newFilename)
+	assert.NoError(t, err)
+	assert.Zero(t, info.Mode().Perm()&^0o640)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm()&0o600)
+}
+
+func TestWriteFileSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated privileges on windows")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target.yaml")
+	link := filepath.Join(dir, "link.yaml")
+	assert.NoError(t, os.WriteFile(target, []byte("old"), 0o600))
+	assert.NoError(t, os.Symlink(target, link))
+
+	err := WriteFile(link, []byte("new"), 0o600)
+	assert.NoError(t, err)
+
+	info, err := os.Lstat(link)
+	assert.NoError(t, err)
+	assert.NotZero(t, info.Mode()&os.ModeSymlink)
+	data, err := os.ReadFile(target)
+	assert.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+}
+
+func TestWriteFileMissingDir(t *testing.T) {
+	err := WriteFile(filepath.Join(t.TempDir(), "missing", "config.yaml"), []byte("data"), 0o600)
+	assert.ErrorContains(t, err, "failed to create temporary file")
+}
@@ -6,11 +6,19 @@ package kubeconfig
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
+const (
+	// EnvKubeConfig is the environment variable listing the kubeconfig files to use instead of the default one
+	EnvKubeConfig = "KUBECONFIG"
+	// RecommendedHomeFile is the path of the default kubeconfig file relative to the home directory
+	RecommendedHomeFile = ".kube/config"
+)
+
 // ReadKubeConfig reads the kubeconfig file and returns the Config
 func ReadKubeConfig(path string) (*Config, error) {
 	kubeconfig, err := os.ReadFile(path)
@@ -84,3 +92,57 @@ func GetAuthInfo(kubeconfig *Config, userName string) *AuthInfo {
 	}
 	return nil
 }
+
+// ReadDefaultKubeConfig reads the kubeconfig with the default loading rules of client-go.
+// The files listed in KUBECONFIG are merged, the first file to set a value wins and missing files are ignored.
+// If KUBECONFIG is not set, the RecommendedHomeFile is read. An empty Config is returned if no file exists.
+func ReadDefaultKubeConfig() (*Config, error) {
+	var paths []string
+	if kubeconfigEnv := os.Getenv(EnvKubeConfig); kubeconfigEnv != "" {
+		paths = filepath.SplitList(kubeconfigEnv)
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, errors.Wrap(err, "could not locate the default kubeconfig")
+		}
+		paths = []string{filepath.Join(home, RecommendedHomeFile)}
+	}
+
+	merged := &Config{}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		config, err := ReadKubeConfig(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the kubeconfig %q", path)
+		}
+		mergeKubeConfig(merged, config)
+	}
+	return merged, nil
+}
+
+// mergeKubeConfig adds the current context, contexts, clusters and users of src not already set in dst
+func mergeKubeConfig(dst, src *Config) {
+	if dst.CurrentContext == "" {
+		dst.CurrentContext = src.CurrentContext
+	}
+	for _, context := range src.Contexts {
+		if GetContext(dst, context.Name) == nil {
+			dst.Contexts = append(dst.Contexts, context)
+		}
+	}
+	for _, cluster := range src.Clusters {
+		if GetCluster(dst, cluster.Name) == nil {
+			dst.Clusters = append(dst.Clusters, cluster)
+		}
+	}
+	for _, authInfo := range src.AuthInfos {
+		if GetAuthInfo(dst, authInfo.Name) == nil {
+			dst.AuthInfos = append(dst.AuthInfos, authInfo)
+		}
+	}
+}
@@ -4,8 +4,6 @@
 package config
 
 import (
-	"strings"
-
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 
@@ -46,6 +44,9 @@ func GetConfigMetadataPatchStrategy() (map[string]string, error) {
 }
 
 // SetConfigMetadataPatchStrategy add or update patch strategy specified by key-value pair
+// The key is the dot separated path (e.g. contexts.clusterOpts, clientOptions.features.*) or the JSON Pointer
+// (e.g. /contexts/clusterOpts) of the config node. The value is one of replace, merge, merge:<merge key> (e.g.
+// merge:name to merge the contexts by name), append, prepend or set-union (see nodeutils.ParsePatchStrategy).
 func SetConfigMetadataPatchStrategy(key, value string) error {
 	// Retrieve config metadata node
 	AcquireTanzuMetadataLock()
@@ -130,8 +131,11 @@ func setConfigMetadataPatchStrategy(node *yaml.Node, key, value string) error {
 		return errors.New("key cannot be empty")
 	}
 
-	if !strings.EqualFold(value, "replace") && !strings.EqualFold(value, "merge") {
-		return errors.New("allowed values are replace or merge")
+	if _, err := nodeutils.ParsePatchStrategyPath(key); err != nil {
+		return err
+	}
+	if _, err := nodeutils.ParsePatchStrategy(value); err != nil {
+		return err
 	}
 
 	// find patch strategy node
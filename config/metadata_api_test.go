@@ -159,7 +159,7 @@ func TestSetConfigMetadataPatchStrategy(t *testing.T) {
 			name:   "failed add new patch strategy invalid value",
 			key:    "contexts.clusterOpts.annotation",
 			value:  "add",
-			errStr: "allowed values are replace or merge",
+			errStr: `invalid patch strategy "add": allowed values are replace, merge, merge:<merge key>, append, prepend or set-union`,
 		},
 	}
 	for _, spec := range tests {
@@ -5,7 +5,6 @@ package nodeutils
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
@@ -49,7 +48,7 @@ func deleteNodes(src, dst *yaml.Node, patchStrategyKey string, patchStrategies m
 
 				// check for patch strategy before performing deep replace
 				key = fmt.Sprintf("%v.%v", key, dst.Content[i].Value)
-				if strings.EqualFold(patchStrategies[key], "replace") {
+				if isReplacePatchStrategy(patchStrategies, key) {
 					dst.Content = append(dst.Content[:i], dst.Content[i+2:]...)
 					i -= 2
 					break
@@ -64,7 +63,7 @@ func deleteNodes(src, dst *yaml.Node, patchStrategyKey string, patchStrategies m
 			// if match not found remove the node if it is found in patch strategy
 			if !found {
 				key = fmt.Sprintf("%v.%v", key, dst.Content[i].Value)
-				if strings.EqualFold(patchStrategies[key], "replace") {
+				if isReplacePatchStrategy(patchStrategies, key) {
 					dst.Content = append(dst.Content[:i], dst.Content[i+2:]...)
 					i -= 2
 				}
@@ -72,6 +71,20 @@ func deleteNodes(src, dst *yaml.Node, patchStrategyKey string, patchStrategies m
 		}
 	case yaml.ScalarNode:
 	case yaml.SequenceNode:
+		// delete nodes in the elements matched by merge key as per patch strategy
+		strategy, ok := LookupPatchStrategy(patchStrategies, patchStrategyKey)
+		if !ok || strategy.Strategy != PatchStrategyMerge || strategy.MergeKey == "" {
+			break
+		}
+		for _, dstElement := range dst.Content {
+			srcElement := findElementByMergeKey(src.Content, dstElement, strategy.MergeKey)
+			if srcElement == nil || srcElement.Kind != dstElement.Kind {
+				continue
+			}
+			if err := deleteNodes(srcElement, dstElement, patchStrategyKey, patchStrategies); err != nil {
+				return errors.Wrap(err, "delete at element "+getMergeKeyValue(dstElement, strategy.MergeKey))
+			}
+		}
 	case yaml.DocumentNode:
 		err := deleteNodes(src.Content[0], dst.Content[0], patchStrategyKey, patchStrategies)
 		if err != nil {
@@ -0,0 +1,262 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangeOp is the operation of a change between two yaml nodes
+type ChangeOp string
+
+const (
+	// ChangeOpAdd adds the value at the path
+	ChangeOpAdd ChangeOp = "add"
+	// ChangeOpRemove removes the value at the path
+	ChangeOpRemove ChangeOp = "remove"
+	// ChangeOpReplace replaces the value at the path
+	ChangeOpReplace ChangeOp = "replace"
+)
+
+// KeyName is the key identifying the elements of a sequence
+const KeyName = "name"
+
+// Change is a change between two yaml nodes
+type Change struct {
+	// Op is the operation of the change
+	Op ChangeOp
+	// Path is the JSON Pointer (RFC 6901) of the changed node e.g. /clientOptions/cli/discoverySources/0
+	Path string
+	// OldValue is the value of the node before the change, set for the remove and replace operations
+	OldValue *yaml.Node
+	// Value is the value of the node after the change, set for the add and replace operations
+	Value *yaml.Node
+}
+
+// Diff returns the changes transforming the node a into the node b. The changes are ordered so that
+// applying them in order (see ApplyChanges) transforms a into b, as with a JSON Patch.
+// Mapping nodes are compared key by key. The elements of sequence nodes are matched by name when all the
+// elements have a name, either as a "name" key or as the "name" key of the value of their single key
+// (e.g. `- oci: {name: default}`), and by index otherwise. Any other node is compared by tag and value.
+func Diff(a, b *yaml.Node) []Change {
+	var changes []Change
+	diffNodes("", unwrapNode(a), unwrapNode(b), &changes)
+	return changes
+}
+
+func diffNodes(path string, a, b *yaml.Node, changes *[]Change) {
+	switch {
+	case a == nil && b == nil:
+		return
+	case a == nil:
+		*changes = append(*changes, Change{Op: ChangeOpAdd, Path: path, Value: b})
+		return
+	case b == nil:
+		*changes = append(*changes, Change{Op: ChangeOpRemove, Path: path, OldValue: a})
+		return
+	}
+
+	switch {
+	case a.Kind == yaml.MappingNode && b.Kind == yaml.MappingNode:
+		diffMappingNodes(path, a, b, changes)
+	case a.Kind == yaml.SequenceNode && b.Kind == yaml.SequenceNode:
+		diffSequenceNodes(path, a, b, changes)
+	case !nodesEqual(a, b):
+		*changes = append(*changes, Change{Op: ChangeOpReplace, Path: path, OldValue: a, Value: b})
+	}
+}
+
+func diffMappingNodes(path string, a, b *yaml.Node, changes *[]Change) {
+	for i := 0; i+1 < len(a.Content); i += 2 {
+		key := a.Content[i].Value
+		var bValue *yaml.Node
+		if idx := GetNodeIndex(b.Content, key); idx != -1 {
+			bValue = unwrapNode(b.Content[idx])
+		}
+		diffNodes(JoinPointer(path, key), unwrapNode(a.Content[i+1]), bValue, changes)
+	}
+	for i := 0; i+1 < len(b.Content); i += 2 {
+		key := b.Content[i].Value
+		if GetNodeIndex(a.Content, key) == -1 {
+			*changes = append(*changes, Change{Op: ChangeOpAdd, Path: JoinPointer(path, key), Value: unwrapNode(b.Content[i+1])})
+		}
+	}
+}
+
+func diffSequenceNodes(path string, a, b *yaml.Node, changes *[]Change) {
+	aNames, aNamed := sequenceElementNames(a)
+	bNames, bNamed := sequenceElementNames(b)
+	if !aNamed || !bNamed {
+		diffSequenceNodesByIndex(path, a, b, changes)
+		return
+	}
+
+	aIndexes := make(map[string]int, len(aNames))
+	for i, name := range aNames {
+		aIndexes[name] = i
+	}
+	bIndexes := make(map[string]int, len(bNames))
+	for i, name := range bNames {
+		bIndexes[name] = i
+	}
+
+	// The matched elements must be in the same order, otherwise the sequence is replaced as a whole
+	last := -1
+	for _, name := range bNames {
+		if i, ok := aIndexes[name]; ok {
+			if i < last {
+				*changes = append(*changes, Change{Op: ChangeOpReplace, Path: path, OldValue: a, Value: b})
+				return
+			}
+			last = i
+		}
+	}
+
+	// Remove the elements missing in b from the last to keep the indexes of the preceding elements valid,
+	// then add the elements missing in a at their index in b, and finally diff the matched elements.
+	for i := len(aNames) - 1; i >= 0; i-- {
+		if _, ok := bIndexes[aNames[i]]; !ok {
+			*changes = append(*changes, Change{Op: ChangeOpRemove, Path: JoinPointer(path, strconv.Itoa(i)), OldValue: unwrapNode(a.Content[i])})
+		}
+	}
+	for i, name := range bNames {
+		if _, ok := aIndexes[name]; !ok {
+			*changes = append(*changes, Change{Op: ChangeOpAdd, Path: JoinPointer(path, strconv.Itoa(i)), Value: unwrapNode(b.Content[i])})
+		}
+	}
+	for i, name := range bNames {
+		if j, ok := aIndexes[name]; ok {
+			diffNodes(JoinPointer(path, strconv.Itoa(i)), unwrapNode(a.Content[j]), unwrapNode(b.Content[i]), changes)
+		}
+	}
+}
+
+func diffSequenceNodesByIndex(path string, a, b *yaml.Node, changes *[]Change) {
+	common := len(a.Content)
+	if len(b.Content) < common {
+		common = len(b.Content)
+	}
+	for i := 0; i < common; i++ {
+		diffNodes(JoinPointer(path, strconv.Itoa(i)), unwrapNode(a.Content[i]), unwrapNode(b.Content[i]), changes)
+	}
+	for i := common; i < len(b.Content); i++ {
+		*changes = append(*changes, Change{Op: ChangeOpAdd, Path: JoinPointer(path, strconv.Itoa(i)), Value: unwrapNode(b.Content[i])})
+	}
+	for i := len(a.Content) - 1; i >= common; i-- {
+		*changes = append(*changes, Change{Op: ChangeOpRemove, Path: JoinPointer(path, strconv.Itoa(i)), OldValue: unwrapNode(a.Content[i])})
+	}
+}
+
+// sequenceElementNames returns the names of the elements of the sequence and true if all the
+// elements have a unique name
+func sequenceElementNames(node *yaml.Node) ([]string, bool) {
+	names := make([]string, 0, len(node.Content))
+	seen := make(map[string]bool, len(node.Content))
+	for _, element := range node.Content {
+		name, ok := sequenceElementName(unwrapNode(element))
+		if !ok || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
+
+// sequenceElementName returns the name of the element e.g. `name: default` or `oci: {name: default}`
+func sequenceElementName(node *yaml.Node) (string, bool) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return "", false
+	}
+	if idx := GetNodeIndex(node.Content, KeyName); idx != -1 && node.Content[idx].Kind == yaml.ScalarNode {
+		return node.Content[idx].Value, true
+	}
+	if len(node.Content) == 2 {
+		if name, ok := sequenceElementName(unwrapNode(node.Content[1])); ok {
+			return node.Content[0].Value + "/" + name, true
+		}
+	}
+	return "", false
+}
+
+// nodesEqual returns true if the nodes have the same kind, tag and value, and equal content.
+// The keys of mapping nodes are compared regardless of their order.
+func nodesEqual(a, b *yaml.Node) bool {
+	a, b = unwrapNode(a), unwrapNode(b)
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case yaml.ScalarNode:
+		return a.ShortTag() == b.ShortTag() && a.Value == b.Value
+	case yaml.MappingNode:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := 0; i+1 < len(a.Content); i += 2 {
+			idx := GetNodeIndex(b.Content, a.Content[i].Value)
+			if idx == -1 || !nodesEqual(a.Content[i+1], b.Content[idx]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if len(a.Content) != len(b.Content) {
+			return false
+		}
+		for i := range a.Content {
+			if !nodesEqual(a.Content[i], b.Content[i]) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// unwrapNode returns the content of a document node and the target of an alias node.
+// nil is returned for an empty document.
+func unwrapNode(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch {
+		case node.Kind == yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+			node = node.Content[0]
+		case node.Kind == yaml.AliasNode:
+			node = node.Alias
+		case node.Kind == 0:
+			return nil
+		default:
+			return node
+		}
+	}
+	return nil
+}
+
+// JoinPointer appends the escaped reference token to the JSON Pointer
+func JoinPointer(pointer, token string) string {
+	return pointer + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// SplitPointer returns the unescaped reference tokens of the JSON Pointer
+func SplitPointer(pointer string) ([]string, bool) {
+	if pointer == "" {
+		return nil, true
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, false
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, true
+}
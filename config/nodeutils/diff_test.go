@@ -0,0 +1,186 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func parseNode(t *testing.T, data string) *yaml.Node {
+	node := &yaml.Node{}
+	require.NoError(t, yaml.Unmarshal([]byte(data), node))
+	return node
+}
+
+type expectedChange struct {
+	op       ChangeOp
+	path     string
+	oldValue string
+	value    string
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected []expectedChange
+	}{
+		{
+			name:     "equal documents with different key order",
+			a:        "a: 1\nb:\n  c: x\n",
+			b:        "b:\n  c: x\na: 1\n",
+			expected: nil,
+		},
+		{
+			name: "nested added, removed and replaced keys",
+			a:    "a: 1\nb:\n  c: x\n  d: y\n",
+			b:    "a: 2\nb:\n  c: x\n  e: z\n",
+			expected: []expectedChange{
+				{op: ChangeOpReplace, path: "/a", oldValue: "1", value: "2"},
+				{op: ChangeOpRemove, path: "/b/d", oldValue: "y"},
+				{op: ChangeOpAdd, path: "/b/e", value: "z"},
+			},
+		},
+		{
+			name: "scalar with a different tag",
+			a:    "a: 1\n",
+			b:    "a: \"1\"\n",
+			expected: []expectedChange{
+				{op: ChangeOpReplace, path: "/a", oldValue: "1", value: "\"1\""},
+			},
+		},
+		{
+			name: "escaped keys",
+			a:    "a/b: 1\nc~d: 2\n",
+			b:    "a/b: 2\n",
+			expected: []expectedChange{
+				{op: ChangeOpReplace, path: "/a~1b", oldValue: "1", value: "2"},
+				{op: ChangeOpRemove, path: "/c~0d", oldValue: "2"},
+			},
+		},
+		{
+			name: "sequence elements matched by index",
+			a:    "s: [1, 2, 3]\n",
+			b:    "s: [1, 4]\n",
+			expected: []expectedChange{
+				{op: ChangeOpReplace, path: "/s/1", oldValue: "2", value: "4"},
+				{op: ChangeOpRemove, path: "/s/2", oldValue: "3"},
+			},
+		},
+		{
+			name: "sequence elements matched by name",
+			a: `contexts:
+  - name: a
+    target: k8s
+  - name: b
+    target: k8s
+  - name: c
+    target: k8s
+`,
+			b: `contexts:
+  - name: a
+    target: tmc
+  - name: d
+    target: k8s
+  - name: c
+    target: k8s
+`,
+			expected: []expectedChange{
+				{op: ChangeOpRemove, path: "/contexts/1", oldValue: "name: b\ntarget: k8s"},
+				{op: ChangeOpAdd, path: "/contexts/1", value: "name: d\ntarget: k8s"},
+				{op: ChangeOpReplace, path: "/contexts/0/target", oldValue: "k8s", value: "tmc"},
+			},
+		},
+		{
+			name: "sequence elements matched by the name of their single key",
+			a: `discoverySources:
+  - oci:
+      name: default
+      image: example.com/default:v1
+  - local:
+      name: local
+      path: /tmp
+`,
+			b: `discoverySources:
+  - local:
+      name: new
+      path: /tmp
+  - oci:
+      name: default
+      image: example.com/default:v2
+`,
+			expected: []expectedChange{
+				{op: ChangeOpRemove, path: "/discoverySources/1", oldValue: "local:\n    name: local\n    path: /tmp"},
+				{op: ChangeOpAdd, path: "/discoverySources/0", value: "local:\n    name: new\n    path: /tmp"},
+				{op: ChangeOpReplace, path: "/discoverySources/1/oci/image", oldValue: "example.com/default:v1", value: "example.com/default:v2"},
+			},
+		},
+		{
+			name: "reordered sequence elements",
+			a:    "s:\n  - name: a\n  - name: b\n",
+			b:    "s:\n  - name: b\n  - name: a\n",
+			expected: []expectedChange{
+				{op: ChangeOpReplace, path: "/s", oldValue: "- name: a\n- name: b", value: "- name: b\n- name: a"},
+			},
+		},
+		{
+			name: "empty document",
+			a:    "",
+			b:    "a: 1\n",
+			expected: []expectedChange{
+				{op: ChangeOpAdd, path: "", value: "a: 1"},
+			},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			a := parseNode(t, spec.a)
+			b := parseNode(t, spec.b)
+
+			changes := Diff(a, b)
+			actual := make([]expectedChange, 0, len(changes))
+			for _, c := range changes {
+				actual = append(actual, expectedChange{op: c.Op, path: c.Path, oldValue: marshalNode(t, c.OldValue), value: marshalNode(t, c.Value)})
+			}
+			if spec.expected == nil {
+				spec.expected = []expectedChange{}
+			}
+			assert.Equal(t, spec.expected, actual)
+
+			// Applying the changes to a results in b
+			require.NoError(t, ApplyChanges(a, changes))
+			assert.True(t, nodesEqual(a, b), "expected:\n%s\nactual:\n%s", spec.b, marshalNode(t, a))
+		})
+	}
+}
+
+func TestPointers(t *testing.T) {
+	pointer := JoinPointers([]string{"a/b", "c~d", "0"})
+	assert.Equal(t, "/a~1b/c~0d/0", pointer)
+
+	tokens, ok := SplitPointer(pointer)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a/b", "c~d", "0"}, tokens)
+
+	tokens, ok = SplitPointer("")
+	assert.True(t, ok)
+	assert.Empty(t, tokens)
+
+	_, ok = SplitPointer("a/b")
+	assert.False(t, ok)
+}
+
+func marshalNode(t *testing.T, node *yaml.Node) string {
+	if node == nil {
+		return ""
+	}
+	data, err := yaml.Marshal(node)
+	require.NoError(t, err)
+	return string(data[:len(data)-1])
+}
@@ -4,6 +4,8 @@
 package nodeutils
 
 import (
+	"fmt"
+
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
@@ -14,7 +16,8 @@ var (
 )
 
 // MergeNodes to merge two yaml nodes src(source) to dst(destination) node
-func MergeNodes(src, dst *yaml.Node) (bool, error) {
+// The nodes are merged as per the patch strategies of their path (see LookupPatchStrategy)
+func MergeNodes(src, dst *yaml.Node, opts ...PatchStrategyOpts) (bool, error) {
 	// only replace if the change is not equal to existing
 	mergeUnequalObjects, err := NotEqual(src, dst)
 	if err != nil {
@@ -23,10 +26,15 @@ func MergeNodes(src, dst *yaml.Node) (bool, error) {
 	if !mergeUnequalObjects {
 		return mergeUnequalObjects, nil
 	}
-	return mergeUnequalObjects, mergeNodes(src, dst)
+
+	options := &PatchStrategyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return mergeUnequalObjects, mergeNodes(src, dst, options.Key, options.PatchStrategies)
 }
 
-func mergeNodes(src, dst *yaml.Node) error {
+func mergeNodes(src, dst *yaml.Node, patchStrategyKey string, patchStrategies map[string]string) error {
 	err := checkErrors(src, dst)
 	if err != nil {
 		return err
@@ -38,7 +46,12 @@ func mergeNodes(src, dst *yaml.Node) error {
 			for j := 0; j < len(dst.Content); j += 2 {
 				if ok, _ := equalScalars(src.Content[i], dst.Content[j]); ok {
 					found = true
-					if err := mergeNodes(src.Content[i+1], dst.Content[j+1]); err != nil {
+					key := fmt.Sprintf("%v.%v", patchStrategyKey, src.Content[i].Value)
+					if isReplacePatchStrategy(patchStrategies, key) {
+						dst.Content[j+1] = replacementNode(src.Content[i+1], dst.Content[j+1])
+						break
+					}
+					if err := mergeNodes(src.Content[i+1], dst.Content[j+1], key, patchStrategies); err != nil {
 						return errors.Wrap(err, "merge at key "+src.Content[i].Value)
 					}
 					break
@@ -49,12 +62,17 @@ func mergeNodes(src, dst *yaml.Node) error {
 			}
 		}
 	case yaml.SequenceNode:
-		err := setSeqNode(src, dst)
+		var err error
+		if strategy, ok := LookupPatchStrategy(patchStrategies, patchStrategyKey); ok {
+			err = mergeSeqNodes(src, dst, strategy, patchStrategyKey, patchStrategies)
+		} else {
+			err = setSeqNode(src, dst)
+		}
 		if err != nil {
 			return errors.Wrap(err, "merge at key "+src.Content[0].Value)
 		}
 	case yaml.DocumentNode:
-		err := mergeNodes(src.Content[0], dst.Content[0])
+		err := mergeNodes(src.Content[0], dst.Content[0], patchStrategyKey, patchStrategies)
 		if err != nil {
 			return errors.Wrap(err, "merge at key "+src.Content[0].Value)
 		}
@@ -84,7 +102,7 @@ func setSeqNode(src, dst *yaml.Node) error {
 		}
 	case yaml.SequenceNode:
 		if len(dst.Content) > 0 && dst.Content[0].Kind == yaml.SequenceNode {
-			if err := mergeNodes(src.Content[0], dst.Content[0]); err != nil {
+			if err := mergeNodes(src.Content[0], dst.Content[0], "", nil); err != nil {
 				return errors.New("merge at key " + src.Content[0].Value + " failed with err " + err.Error())
 			}
 		} else {
@@ -93,7 +111,7 @@ func setSeqNode(src, dst *yaml.Node) error {
 
 	case yaml.MappingNode:
 		if len(dst.Content) > 0 && dst.Content[0].Kind == yaml.MappingNode {
-			if err := mergeNodes(src.Content[0], dst.Content[0]); err != nil {
+			if err := mergeNodes(src.Content[0], dst.Content[0], "", nil); err != nil {
 				return errors.New("merge at key " + src.Content[0].Value + " failed with err " + err.Error())
 			}
 		} else {
@@ -62,6 +62,9 @@ const (
 )
 
 const (
-	PatchStrategyReplace = "replace"
-	PatchStrategyMerge   = "merge"
+	PatchStrategyReplace  = "replace"
+	PatchStrategyMerge    = "merge"
+	PatchStrategyAppend   = "append"
+	PatchStrategyPrepend  = "prepend"
+	PatchStrategySetUnion = "set-union"
 )
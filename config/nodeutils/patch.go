@@ -0,0 +1,337 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// jsonPatchOperation is an operation of a JSON Patch (RFC 6902)
+type jsonPatchOperation struct {
+	Op    ChangeOp    `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ToJSONPatch converts the changes to a JSON Patch (RFC 6902) document
+func ToJSONPatch(changes []Change) ([]byte, error) {
+	operations := make([]jsonPatchOperation, 0, len(changes))
+	for _, change := range changes {
+		operation := jsonPatchOperation{Op: change.Op, Path: change.Path}
+		switch change.Op {
+		case ChangeOpAdd, ChangeOpReplace:
+			value, err := nodeToJSONValue(change.Value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to convert the value of %q", change.Path)
+			}
+			// A nil value is omitted, so it is set to the explicit JSON null
+			if value == nil {
+				value = json.RawMessage("null")
+			}
+			operation.Value = value
+		case ChangeOpRemove:
+		default:
+			return nil, errors.Errorf("unsupported operation %q", change.Op)
+		}
+		operations = append(operations, operation)
+	}
+	return json.Marshal(operations)
+}
+
+// ParseJSONPatch parses the JSON Patch (RFC 6902) document into changes. Only the add, remove
+// and replace operations are supported. The document can also be written in YAML.
+func ParseJSONPatch(data []byte) ([]Change, error) {
+	var operations []struct {
+		Op    ChangeOp  `yaml:"op"`
+		Path  string    `yaml:"path"`
+		Value yaml.Node `yaml:"value"`
+	}
+	if err := yaml.Unmarshal(data, &operations); err != nil {
+		return nil, errors.Wrap(err, "failed to parse the JSON patch")
+	}
+	changes := make([]Change, 0, len(operations))
+	for i, operation := range operations {
+		if _, ok := SplitPointer(operation.Path); !ok {
+			return nil, errors.Errorf("operation %d: invalid path %q", i, operation.Path)
+		}
+		change := Change{Op: operation.Op, Path: operation.Path}
+		switch operation.Op {
+		case ChangeOpAdd, ChangeOpReplace:
+			if operation.Value.Kind == 0 {
+				return nil, errors.Errorf("operation %d: %s operation requires a value", i, operation.Op)
+			}
+			value := operation.Value
+			resetStyle(&value)
+			change.Value = &value
+		case ChangeOpRemove:
+		default:
+			return nil, errors.Errorf("operation %d: unsupported operation %q", i, operation.Op)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// ApplyJSONPatch applies the JSON Patch (RFC 6902) document to the node
+func ApplyJSONPatch(node *yaml.Node, patch []byte) error {
+	changes, err := ParseJSONPatch(patch)
+	if err != nil {
+		return err
+	}
+	return ApplyChanges(node, changes)
+}
+
+// ApplyChanges applies the changes in order to the node. The comments of the unchanged nodes are preserved.
+// If the node is a document node, the changes are applied to its content.
+func ApplyChanges(node *yaml.Node, changes []Change) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	for _, change := range changes {
+		if err := applyChange(node, change); err != nil {
+			return errors.Wrapf(err, "failed to %s %q", change.Op, change.Path)
+		}
+	}
+	return nil
+}
+
+func applyChange(node *yaml.Node, change Change) error {
+	tokens, ok := SplitPointer(change.Path)
+	if !ok {
+		return errors.New("invalid path")
+	}
+	root := documentContent(node)
+
+	if len(tokens) == 0 {
+		if change.Op == ChangeOpRemove {
+			return errors.New("cannot remove the root node")
+		}
+		*root = *change.Value
+		return nil
+	}
+
+	parent := root
+	for i, token := range tokens[:len(tokens)-1] {
+		child, err := getChildNode(parent, token)
+		if err != nil {
+			return errors.Wrapf(err, "path %q", JoinPointers(tokens[:i+1]))
+		}
+		parent = child
+	}
+
+	last := tokens[len(tokens)-1]
+	switch parent.Kind {
+	case yaml.MappingNode:
+		idx := GetNodeIndex(parent.Content, last)
+		switch {
+		case change.Op == ChangeOpAdd && idx == -1:
+			parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: NodeTagStr, Value: last}, change.Value)
+		case idx == -1:
+			return ErrNodeNotFound
+		case change.Op == ChangeOpRemove:
+			parent.Content = append(parent.Content[:idx-1], parent.Content[idx+1:]...)
+		default:
+			parent.Content[idx] = change.Value
+		}
+	case yaml.SequenceNode:
+		if change.Op == ChangeOpAdd && last == "-" {
+			parent.Content = append(parent.Content, change.Value)
+			return nil
+		}
+		idx, err := strconv.Atoi(last)
+		if err != nil || idx < 0 || idx > len(parent.Content) || (idx == len(parent.Content) && change.Op != ChangeOpAdd) {
+			return errors.Errorf("invalid index %q", last)
+		}
+		switch change.Op {
+		case ChangeOpAdd:
+			parent.Content = append(parent.Content[:idx], append([]*yaml.Node{change.Value}, parent.Content[idx:]...)...)
+		case ChangeOpRemove:
+			parent.Content = append(parent.Content[:idx], parent.Content[idx+1:]...)
+		default:
+			parent.Content[idx] = change.Value
+		}
+	default:
+		return errors.Errorf("parent is not a mapping or a sequence")
+	}
+	return nil
+}
+
+// documentContent returns the content of the document node, the node itself if it is not a document.
+// An empty node or document is initialized with an empty mapping node.
+func documentContent(node *yaml.Node) *yaml.Node {
+	if node.Kind == 0 {
+		node.Kind = yaml.DocumentNode
+	}
+	if node.Kind != yaml.DocumentNode {
+		return node
+	}
+	if len(node.Content) == 0 {
+		node.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+	return node.Content[0]
+}
+
+// getChildNode returns the child node of the mapping or sequence node referenced by the token
+func getChildNode(node *yaml.Node, token string) (*yaml.Node, error) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		idx := GetNodeIndex(node.Content, token)
+		if idx == -1 {
+			return nil, ErrNodeNotFound
+		}
+		return unwrapNode(node.Content[idx]), nil
+	case yaml.SequenceNode:
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= len(node.Content) {
+			return nil, errors.Errorf("invalid index %q", token)
+		}
+		return unwrapNode(node.Content[idx]), nil
+	}
+	return nil, errors.New("not a mapping or a sequence")
+}
+
+// JoinPointers returns the JSON Pointer of the unescaped reference tokens
+func JoinPointers(tokens []string) string {
+	pointer := ""
+	for _, token := range tokens {
+		pointer = JoinPointer(pointer, token)
+	}
+	return pointer
+}
+
+// CreateMergePatch returns the JSON Merge Patch (RFC 7386) document transforming the node a into the node b
+func CreateMergePatch(a, b *yaml.Node) ([]byte, error) {
+	patch, err := createMergePatch(unwrapNode(a), unwrapNode(b))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(patch)
+}
+
+func createMergePatch(a, b *yaml.Node) (interface{}, error) {
+	if a == nil || b == nil || a.Kind != yaml.MappingNode || b.Kind != yaml.MappingNode {
+		return nodeToJSONValue(b)
+	}
+	patch := make(map[string]interface{})
+	for i := 0; i+1 < len(a.Content); i += 2 {
+		if GetNodeIndex(b.Content, a.Content[i].Value) == -1 {
+			patch[a.Content[i].Value] = nil
+		}
+	}
+	for i := 0; i+1 < len(b.Content); i += 2 {
+		key := b.Content[i].Value
+		bValue := unwrapNode(b.Content[i+1])
+		var aValue *yaml.Node
+		if idx := GetNodeIndex(a.Content, key); idx != -1 {
+			aValue = unwrapNode(a.Content[idx])
+			if nodesEqual(aValue, bValue) {
+				continue
+			}
+		}
+		value, err := createMergePatch(aValue, bValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %q", key)
+		}
+		patch[key] = value
+	}
+	return patch, nil
+}
+
+// ApplyMergePatch applies the JSON Merge Patch (RFC 7386) document to the node. The comments of the
+// unchanged nodes are preserved. The document can also be written in YAML.
+func ApplyMergePatch(node *yaml.Node, patch []byte) error {
+	if node == nil {
+		return errors.New("node cannot be nil")
+	}
+	patchNode := &yaml.Node{}
+	if err := yaml.Unmarshal(patch, patchNode); err != nil {
+		return errors.Wrap(err, "failed to parse the merge patch")
+	}
+	patchNode = unwrapNode(patchNode)
+	if patchNode == nil {
+		return nil
+	}
+	resetStyle(patchNode)
+
+	root := documentContent(node)
+	*root = *applyMergePatch(root, patchNode)
+	return nil
+}
+
+// applyMergePatch returns the target node patched with the patch node
+func applyMergePatch(target, patch *yaml.Node) *yaml.Node {
+	if patch.Kind != yaml.MappingNode {
+		return patch
+	}
+	if target == nil || target.Kind != yaml.MappingNode {
+		target = &yaml.Node{Kind: yaml.MappingNode}
+	}
+	for i := 0; i+1 < len(patch.Content); i += 2 {
+		key := patch.Content[i]
+		value := unwrapNode(patch.Content[i+1])
+		idx := GetNodeIndex(target.Content, key.Value)
+		switch {
+		case isNullNode(value):
+			if idx != -1 {
+				target.Content = append(target.Content[:idx-1], target.Content[idx+1:]...)
+			}
+		case idx == -1:
+			target.Content = append(target.Content, key, applyMergePatch(nil, value))
+		default:
+			target.Content[idx] = applyMergePatch(unwrapNode(target.Content[idx]), value)
+		}
+	}
+	return target
+}
+
+func isNullNode(node *yaml.Node) bool {
+	return node == nil || (node.Kind == yaml.ScalarNode && node.ShortTag() == "!!null")
+}
+
+// nodeToJSONValue decodes the node into a value which can be marshaled to JSON
+func nodeToJSONValue(node *yaml.Node) (interface{}, error) {
+	node = unwrapNode(node)
+	if node == nil {
+		return nil, nil
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := nodeToJSONValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[node.Content[i].Value] = value
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		s := make([]interface{}, 0, len(node.Content))
+		for _, element := range node.Content {
+			value, err := nodeToJSONValue(element)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, value)
+		}
+		return s, nil
+	}
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// resetStyle resets the style of the node and its content so that it is encoded in the block style
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetStyle(n)
+	}
+}
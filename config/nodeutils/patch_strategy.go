@@ -0,0 +1,214 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// PatchStrategyWildcard is the key of a patch strategy path matching any key
+const PatchStrategyWildcard = "*"
+
+// PatchStrategy is the strategy used to patch the node at a path
+type PatchStrategy struct {
+	// Strategy is one of merge, replace, append, prepend and set-union
+	Strategy string
+	// MergeKey is the key identifying the elements of a list merged element-wise e.g. name for contexts
+	MergeKey string
+}
+
+// ParsePatchStrategy parses the value of a patch strategy. The allowed values are:
+//   - replace: the node is replaced
+//   - merge: maps are merged key by key, lists of scalars are merged as a set
+//   - merge:<merge key>: in addition, the map elements of lists are merged element-wise by the value of the merge key
+//   - append, prepend: the list elements which are not in the list are added at the end or at the start of the list,
+//     so that merging the same list again, as the setters do with the full object, does not duplicate them
+//   - set-union: the list elements which are not in the list are added at the end of the list
+func ParsePatchStrategy(value string) (PatchStrategy, error) {
+	strategy, mergeKey, hasMergeKey := strings.Cut(value, ":")
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	mergeKey = strings.TrimSpace(mergeKey)
+	switch strategy {
+	case PatchStrategyMerge:
+		if hasMergeKey && mergeKey == "" {
+			return PatchStrategy{}, errors.Errorf("invalid patch strategy %q: merge key cannot be empty", value)
+		}
+	case PatchStrategyReplace, PatchStrategyAppend, PatchStrategyPrepend, PatchStrategySetUnion:
+		if hasMergeKey {
+			return PatchStrategy{}, errors.Errorf("invalid patch strategy %q: merge key is only allowed with the merge strategy", value)
+		}
+	default:
+		return PatchStrategy{}, errors.Errorf("invalid patch strategy %q: allowed values are replace, merge, merge:<merge key>, append, prepend or set-union", value)
+	}
+	return PatchStrategy{Strategy: strategy, MergeKey: mergeKey}, nil
+}
+
+// ParsePatchStrategyPath returns the keys of the path of a patch strategy. The path is either dot separated
+// e.g. contexts.clusterOpts or a JSON Pointer e.g. /contexts/clusterOpts. The list elements are not part of the
+// path and a "*" key matches any key e.g. clientOptions.features.*.
+func ParsePatchStrategyPath(path string) ([]string, error) {
+	var keys []string
+	if strings.HasPrefix(path, "/") {
+		keys, _ = SplitPointer(path)
+	} else {
+		keys = strings.Split(path, ".")
+	}
+	for _, key := range keys {
+		if key == "" {
+			return nil, errors.Errorf("invalid patch strategy path %q: empty key", path)
+		}
+	}
+	return keys, nil
+}
+
+// LookupPatchStrategy returns the patch strategy of the node at the dot separated path. An exact match of the
+// path is preferred over a match with wildcards, and the match with the fewest wildcards is preferred over the
+// others. The invalid patch strategies are ignored.
+func LookupPatchStrategy(patchStrategies map[string]string, path string) (PatchStrategy, bool) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" || len(patchStrategies) == 0 {
+		return PatchStrategy{}, false
+	}
+	if value, ok := patchStrategies[path]; ok {
+		if strategy, err := ParsePatchStrategy(value); err == nil {
+			return strategy, true
+		}
+	}
+
+	keys := strings.Split(path, ".")
+	type match struct {
+		path      string
+		wildcards int
+		strategy  PatchStrategy
+	}
+	var matches []match
+	for strategyPath, value := range patchStrategies {
+		strategyKeys, err := ParsePatchStrategyPath(strategyPath)
+		if err != nil || len(strategyKeys) != len(keys) {
+			continue
+		}
+		wildcards := 0
+		for i, key := range strategyKeys {
+			if key == PatchStrategyWildcard {
+				wildcards++
+			} else if key != keys[i] {
+				wildcards = -1
+				break
+			}
+		}
+		if wildcards == -1 {
+			continue
+		}
+		strategy, err := ParsePatchStrategy(value)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, match{path: strategyPath, wildcards: wildcards, strategy: strategy})
+	}
+	if len(matches) == 0 {
+		return PatchStrategy{}, false
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].wildcards != matches[j].wildcards {
+			return matches[i].wildcards < matches[j].wildcards
+		}
+		return matches[i].path < matches[j].path
+	})
+	return matches[0].strategy, true
+}
+
+// isReplacePatchStrategy returns true if the patch strategy of the node at the path is replace
+func isReplacePatchStrategy(patchStrategies map[string]string, path string) bool {
+	strategy, ok := LookupPatchStrategy(patchStrategies, path)
+	return ok && strategy.Strategy == PatchStrategyReplace
+}
+
+// replacementNode returns a copy of the src node replacing the dst node. The comments of the dst node are kept
+// unless the src node has its own comments.
+func replacementNode(src, dst *yaml.Node) *yaml.Node {
+	replacement := *src
+	if replacement.HeadComment == "" && replacement.LineComment == "" && replacement.FootComment == "" {
+		replacement.HeadComment = dst.HeadComment
+		replacement.LineComment = dst.LineComment
+		replacement.FootComment = dst.FootComment
+	}
+	return &replacement
+}
+
+// mergeSeqNodes merges the src sequence node into the dst sequence node as per the patch strategy
+func mergeSeqNodes(src, dst *yaml.Node, strategy PatchStrategy, patchStrategyKey string, patchStrategies map[string]string) error {
+	switch strategy.Strategy {
+	case PatchStrategyReplace:
+		dst.Content = src.Content
+	case PatchStrategyAppend, PatchStrategySetUnion:
+		dst.Content = append(dst.Content, missingElements(src.Content, dst.Content)...)
+	case PatchStrategyPrepend:
+		dst.Content = append(missingElements(src.Content, dst.Content), dst.Content...)
+	default:
+		if strategy.MergeKey == "" {
+			return setSeqNode(src, dst)
+		}
+		for _, srcElement := range src.Content {
+			dstElement := findElementByMergeKey(dst.Content, srcElement, strategy.MergeKey)
+			if dstElement == nil {
+				dst.Content = append(dst.Content, srcElement)
+				continue
+			}
+			if err := mergeNodes(srcElement, dstElement, patchStrategyKey, patchStrategies); err != nil {
+				return errors.Wrapf(err, "merge of element %q", getMergeKeyValue(srcElement, strategy.MergeKey))
+			}
+		}
+	}
+	return nil
+}
+
+// missingElements returns the elements which are not equal to any of the existing elements or the previous elements
+func missingElements(elements, existing []*yaml.Node) []*yaml.Node {
+	var missing []*yaml.Node
+	for _, element := range elements {
+		if indexOfEqualNode(existing, element) == -1 && indexOfEqualNode(missing, element) == -1 {
+			missing = append(missing, element)
+		}
+	}
+	return missing
+}
+
+// findElementByMergeKey returns the mapping node of the elements with the same value of the merge key as the element
+func findElementByMergeKey(elements []*yaml.Node, element *yaml.Node, mergeKey string) *yaml.Node {
+	value := getMergeKeyValue(element, mergeKey)
+	if value == "" {
+		return nil
+	}
+	for _, e := range elements {
+		if getMergeKeyValue(e, mergeKey) == value {
+			return e
+		}
+	}
+	return nil
+}
+
+// getMergeKeyValue returns the value of the merge key of the mapping node, empty if not found
+func getMergeKeyValue(node *yaml.Node, mergeKey string) string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return ""
+	}
+	if idx := GetNodeIndex(node.Content, mergeKey); idx != -1 && node.Content[idx].Kind == yaml.ScalarNode {
+		return node.Content[idx].Value
+	}
+	return ""
+}
+
+// indexOfEqualNode returns the index of the node equal to the node, -1 if not found
+func indexOfEqualNode(nodes []*yaml.Node, node *yaml.Node) int {
+	for i, n := range nodes {
+		if nodesEqual(n, node) {
+			return i
+		}
+	}
+	return -1
+}
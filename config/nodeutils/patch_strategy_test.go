@@ -0,0 +1,242 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePatchStrategy(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected PatchStrategy
+		err      string
+	}{
+		{value: "replace", expected: PatchStrategy{Strategy: PatchStrategyReplace}},
+		{value: "Merge", expected: PatchStrategy{Strategy: PatchStrategyMerge}},
+		{value: "merge:name", expected: PatchStrategy{Strategy: PatchStrategyMerge, MergeKey: "name"}},
+		{value: "append", expected: PatchStrategy{Strategy: PatchStrategyAppend}},
+		{value: "prepend", expected: PatchStrategy{Strategy: PatchStrategyPrepend}},
+		{value: "set-union", expected: PatchStrategy{Strategy: PatchStrategySetUnion}},
+		{value: "merge:", err: `invalid patch strategy "merge:": merge key cannot be empty`},
+		{value: "append:name", err: `invalid patch strategy "append:name": merge key is only allowed with the merge strategy`},
+		{value: "add", err: `invalid patch strategy "add": allowed values are replace, merge, merge:<merge key>, append, prepend or set-union`},
+	}
+	for _, spec := range tests {
+		t.Run(spec.value, func(t *testing.T) {
+			strategy, err := ParsePatchStrategy(spec.value)
+			if spec.err != "" {
+				assert.EqualError(t, err, spec.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expected, strategy)
+		})
+	}
+}
+
+func TestLookupPatchStrategy(t *testing.T) {
+	patchStrategies := map[string]string{
+		"contexts":                     "merge:name",
+		"contexts.additionalMetadata":  "replace",
+		"contexts.*":                   "append",
+		"*.*":                          "prepend",
+		"/clientOptions/features/*":    "set-union",
+		"clientOptions.features.a~1b":  "invalid",
+		"clientOptions.cli.invalid..a": "replace",
+	}
+	tests := []struct {
+		path     string
+		expected PatchStrategy
+		found    bool
+	}{
+		{path: "contexts", expected: PatchStrategy{Strategy: PatchStrategyMerge, MergeKey: "name"}, found: true},
+		{path: ".contexts", expected: PatchStrategy{Strategy: PatchStrategyMerge, MergeKey: "name"}, found: true},
+		{path: "contexts.additionalMetadata", expected: PatchStrategy{Strategy: PatchStrategyReplace}, found: true},
+		{path: "contexts.clusterOpts", expected: PatchStrategy{Strategy: PatchStrategyAppend}, found: true},
+		{path: "servers.clusterOpts", expected: PatchStrategy{Strategy: PatchStrategyPrepend}, found: true},
+		{path: "clientOptions.features.global", expected: PatchStrategy{Strategy: PatchStrategySetUnion}, found: true},
+		{path: "clientOptions.features.a~1b", expected: PatchStrategy{Strategy: PatchStrategySetUnion}, found: true},
+		{path: "clientOptions", found: false},
+		{path: "", found: false},
+	}
+	for _, spec := range tests {
+		t.Run(spec.path, func(t *testing.T) {
+			strategy, found := LookupPatchStrategy(patchStrategies, spec.path)
+			assert.Equal(t, spec.found, found)
+			assert.Equal(t, spec.expected, strategy)
+		})
+	}
+}
+
+func TestMergeNodesWithPatchStrategies(t *testing.T) {
+	dst := `contexts:
+  - name: a
+    target: k8s
+    tags: [x, y]
+    additionalMetadata:
+      one: 1
+  - name: b
+    target: k8s
+    tags: [x]
+`
+	src := `contexts:
+  - name: b
+    target: tmc
+    tags: [y, x]
+    additionalMetadata:
+      two: 2
+  - name: c
+    target: k8s
+`
+	tests := []struct {
+		name            string
+		patchStrategies map[string]string
+		expected        string
+	}{
+		{
+			name:            "merge by merge key and set union",
+			patchStrategies: map[string]string{"contexts": "merge:name", "contexts.tags": "set-union"},
+			expected: `contexts:
+    - name: a
+      target: k8s
+      tags: [x, y]
+      additionalMetadata:
+        one: 1
+    - name: b
+      target: tmc
+      tags: [x, y]
+      additionalMetadata:
+        two: 2
+    - name: c
+      target: k8s
+`,
+		},
+		{
+			name:            "merge by merge key and append",
+			patchStrategies: map[string]string{"contexts": "merge:name", "contexts.*": "append"},
+			expected: `contexts:
+    - name: a
+      target: k8s
+      tags: [x, y]
+      additionalMetadata:
+        one: 1
+    - name: b
+      target: tmc
+      tags: [x, y]
+      additionalMetadata:
+        two: 2
+    - name: c
+      target: k8s
+`,
+		},
+		{
+			name:            "prepend",
+			patchStrategies: map[string]string{"/contexts": "prepend"},
+			expected: `contexts:
+    - name: b
+      target: tmc
+      tags: [y, x]
+      additionalMetadata:
+        two: 2
+    - name: c
+      target: k8s
+    - name: a
+      target: k8s
+      tags: [x, y]
+      additionalMetadata:
+        one: 1
+    - name: b
+      target: k8s
+      tags: [x]
+`,
+		},
+		{
+			name:            "replace",
+			patchStrategies: map[string]string{"contexts": "replace"},
+			expected: `contexts:
+    - name: b
+      target: tmc
+      tags: [y, x]
+      additionalMetadata:
+        two: 2
+    - name: c
+      target: k8s
+`,
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			dstNode := parseNode(t, dst)
+			persist, err := MergeNodes(parseNode(t, src), dstNode, WithPatchStrategies(spec.patchStrategies))
+			require.NoError(t, err)
+			assert.True(t, persist)
+			assert.Equal(t, spec.expected, marshalDocument(t, dstNode))
+		})
+	}
+}
+
+func TestMergeNodesWithReplacePatchStrategyKeepsComments(t *testing.T) {
+	dst := `contexts:
+  # the first context
+  - name: a
+    target: k8s # the target of the context
+    tags: [x] # the tags of the context
+`
+	src := `contexts:
+  - name: a
+    target: tmc
+    tags: [y]
+`
+	dstNode := parseNode(t, dst)
+	patchStrategies := map[string]string{"contexts": "merge:name", "contexts.target": "replace", "contexts.tags": "replace"}
+	persist, err := MergeNodes(parseNode(t, src), dstNode, WithPatchStrategies(patchStrategies))
+	require.NoError(t, err)
+	assert.True(t, persist)
+	assert.Equal(t, `contexts:
+    # the first context
+    - name: a
+      target: tmc # the target of the context
+      tags: [y] # the tags of the context
+`, marshalDocument(t, dstNode))
+
+	// The comments of the src node take precedence
+	dstNode = parseNode(t, dst)
+	persist, err = MergeNodes(parseNode(t, "contexts:\n  - name: a\n    target: tmc # the new target\n"), dstNode, WithPatchStrategies(patchStrategies))
+	require.NoError(t, err)
+	assert.True(t, persist)
+	assert.Contains(t, marshalDocument(t, dstNode), "target: tmc # the new target\n")
+}
+
+func TestDeleteNodesWithMergeKey(t *testing.T) {
+	dst := parseNode(t, `contexts:
+  - name: a
+    additionalMetadata:
+      one: 1
+  - name: b
+    additionalMetadata:
+      one: 1
+`)
+	src := parseNode(t, `contexts:
+  - name: b
+    additionalMetadata:
+      two: 2
+`)
+	patchStrategies := map[string]string{"contexts": "merge:name", "contexts.additionalMetadata": "replace"}
+	_, err := DeleteNodes(src, dst, WithPatchStrategies(patchStrategies))
+	require.NoError(t, err)
+	_, err = MergeNodes(src, dst, WithPatchStrategies(patchStrategies))
+	require.NoError(t, err)
+	assert.Equal(t, `contexts:
+    - name: a
+      additionalMetadata:
+        one: 1
+    - name: b
+      additionalMetadata:
+        two: 2
+`, marshalDocument(t, dst))
+}
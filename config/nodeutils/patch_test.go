@@ -0,0 +1,185 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestJSONPatch(t *testing.T) {
+	a := `# comment of a
+a: 1
+b:
+  c: x # comment of c
+  d: [1, 2]
+contexts:
+  - name: a
+    target: k8s
+  - name: b
+    target: k8s
+`
+	b := `a: 2
+b:
+  c: x
+  d: [1, 2, {e: f}]
+contexts:
+  - name: b
+    target: tmc
+f: null
+`
+	changes := Diff(parseNode(t, a), parseNode(t, b))
+	patch, err := ToJSONPatch(changes)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"op": "replace", "path": "/a", "value": 2},
+		{"op": "add", "path": "/b/d/2", "value": {"e": "f"}},
+		{"op": "remove", "path": "/contexts/0"},
+		{"op": "replace", "path": "/contexts/0/target", "value": "tmc"},
+		{"op": "add", "path": "/f", "value": null}
+	]`, string(patch))
+
+	node := parseNode(t, a)
+	require.NoError(t, ApplyJSONPatch(node, patch))
+	assert.True(t, nodesEqual(node, parseNode(t, b)))
+	assert.Equal(t, `# comment of a
+a: 2
+b:
+    c: x # comment of c
+    d: [1, 2, {e: f}]
+contexts:
+    - name: b
+      target: tmc
+f: null
+`, marshalDocument(t, node))
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		node     string
+		patch    string
+		expected string
+		err      string
+	}{
+		{
+			name:     "add to the end of a sequence",
+			node:     "s: [1]\n",
+			patch:    `[{"op": "add", "path": "/s/-", "value": 2}]`,
+			expected: "s: [1, 2]\n",
+		},
+		{
+			name:     "add to an empty document",
+			node:     "",
+			patch:    `[{"op": "add", "path": "/a", "value": {"b": ["c"]}}]`,
+			expected: "a:\n    b:\n        - c\n",
+		},
+		{
+			name:     "patch written in YAML",
+			node:     "a: 1\n",
+			patch:    "- op: replace\n  path: /a\n  value: \"2\"\n",
+			expected: "a: \"2\"\n",
+		},
+		{
+			name:  "replace a missing key",
+			node:  "a: 1\n",
+			patch: `[{"op": "replace", "path": "/b", "value": 2}]`,
+			err:   `failed to replace "/b": node not found`,
+		},
+		{
+			name:  "remove an out of range index",
+			node:  "s: [1]\n",
+			patch: `[{"op": "remove", "path": "/s/1"}]`,
+			err:   `failed to remove "/s/1": invalid index "1"`,
+		},
+		{
+			name:  "missing parent",
+			node:  "a: 1\n",
+			patch: `[{"op": "add", "path": "/b/c", "value": 2}]`,
+			err:   `failed to add "/b/c": path "/b": node not found`,
+		},
+		{
+			name:  "unsupported operation",
+			node:  "a: 1\n",
+			patch: `[{"op": "move", "from": "/a", "path": "/b"}]`,
+			err:   `operation 0: unsupported operation "move"`,
+		},
+		{
+			name:  "missing value",
+			node:  "a: 1\n",
+			patch: `[{"op": "add", "path": "/b"}]`,
+			err:   `operation 0: add operation requires a value`,
+		},
+		{
+			name:  "invalid path",
+			node:  "a: 1\n",
+			patch: `[{"op": "remove", "path": "a"}]`,
+			err:   `operation 0: invalid path "a"`,
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			node := parseNode(t, spec.node)
+			err := ApplyJSONPatch(node, []byte(spec.patch))
+			if spec.err != "" {
+				assert.EqualError(t, err, spec.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expected, marshalDocument(t, node))
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	a := `a: 1
+b:
+  c: x # comment of c
+  d: y
+s: [1, 2]
+`
+	b := `a: 1
+b:
+  c: x
+  e: z
+s: [2]
+t:
+  u: v
+`
+	patch, err := CreateMergePatch(parseNode(t, a), parseNode(t, b))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"b": {"d": null, "e": "z"}, "s": [2], "t": {"u": "v"}}`, string(patch))
+
+	node := parseNode(t, a)
+	require.NoError(t, ApplyMergePatch(node, patch))
+	assert.True(t, nodesEqual(node, parseNode(t, b)))
+	assert.Equal(t, `a: 1
+b:
+    c: x # comment of c
+    e: z
+s:
+    - 2
+t:
+    u: v
+`, marshalDocument(t, node))
+
+	// Nulls in the values of new keys are removed
+	node = parseNode(t, "a: 1\n")
+	require.NoError(t, ApplyMergePatch(node, []byte(`{"a": null, "b": {"c": null, "d": 1}}`)))
+	assert.Equal(t, "b:\n    d: 1\n", marshalDocument(t, node))
+
+	// A patch which is not an object replaces the document
+	node = parseNode(t, "a: 1\n")
+	require.NoError(t, ApplyMergePatch(node, []byte(`["a"]`)))
+	assert.Equal(t, "- a\n", marshalDocument(t, node))
+}
+
+func marshalDocument(t *testing.T, node *yaml.Node) string {
+	data, err := yaml.Marshal(node)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,274 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// PathSegmentKind is the kind of a segment of a path expression
+type PathSegmentKind int
+
+const (
+	// PathSegmentKey selects the value of a key of a mapping node e.g. clusterOpts
+	PathSegmentKey PathSegmentKind = iota
+	// PathSegmentIndex selects an element of a sequence node by index e.g. [0]
+	PathSegmentIndex
+	// PathSegmentMatch selects the element of a sequence node with a key of the given value e.g. [name=prod]
+	PathSegmentMatch
+)
+
+// PathSegment is a segment of a path expression
+type PathSegment struct {
+	Kind PathSegmentKind
+	// Key is the key of the mapping node, or the key of the sequence element to match
+	Key string
+	// Index is the index of the sequence element
+	Index int
+	// Value is the value of the key of the sequence element to match
+	Value string
+}
+
+// String returns the segment as written in a path expression
+func (s PathSegment) String() string {
+	switch s.Kind {
+	case PathSegmentIndex:
+		return fmt.Sprintf("[%d]", s.Index)
+	case PathSegmentMatch:
+		return fmt.Sprintf("[%s=%s]", s.Key, s.Value)
+	}
+	return s.Key
+}
+
+// ParsePath parses the path expression into segments. The path expression is made of dot separated keys
+// of mapping nodes, each optionally followed by selectors of sequence elements, either by index e.g. [0]
+// or by the value of a key e.g. [name=prod].
+// Ex: contexts[name=prod].clusterOpts.context
+func ParsePath(path string) ([]PathSegment, error) {
+	if path == "" {
+		return nil, errors.New("path cannot be empty")
+	}
+	var segments []PathSegment
+	for i := 0; i < len(path); {
+		if path[i] == '[' {
+			if len(segments) == 0 {
+				return nil, errors.Errorf("invalid path %q: path must start with a key", path)
+			}
+			end := strings.IndexByte(path[i:], ']')
+			if end == -1 {
+				return nil, errors.Errorf("invalid path %q: missing ']'", path)
+			}
+			segment, err := parseSelector(path[i+1 : i+end])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid path %q", path)
+			}
+			segments = append(segments, segment)
+			i += end + 1
+			continue
+		}
+
+		if len(segments) > 0 {
+			if path[i] != '.' {
+				return nil, errors.Errorf("invalid path %q: unexpected %q at offset %d", path, path[i], i)
+			}
+			i++
+		}
+		end := strings.IndexAny(path[i:], ".[]")
+		if end == -1 {
+			end = len(path) - i
+		}
+		if end == 0 {
+			return nil, errors.Errorf("invalid path %q: empty key at offset %d", path, i)
+		}
+		segments = append(segments, PathSegment{Kind: PathSegmentKey, Key: path[i : i+end]})
+		i += end
+	}
+	return segments, nil
+}
+
+// parseSelector parses the selector of a sequence element e.g. 0 or name=prod
+func parseSelector(selector string) (PathSegment, error) {
+	if key, value, ok := strings.Cut(selector, "="); ok {
+		if key == "" {
+			return PathSegment{}, errors.Errorf("empty key in selector %q", selector)
+		}
+		return PathSegment{Kind: PathSegmentMatch, Key: key, Value: value}, nil
+	}
+	index, err := strconv.Atoi(selector)
+	if err != nil || index < 0 {
+		return PathSegment{}, errors.Errorf("invalid selector %q", selector)
+	}
+	return PathSegment{Kind: PathSegmentIndex, Index: index}, nil
+}
+
+// PathPatchStrategyKey returns the key of the patch strategy of the node at the path, made of the dot
+// separated keys of the path without the sequence element selectors.
+// Ex: contexts.clusterOpts for contexts[name=prod].clusterOpts
+func PathPatchStrategyKey(segments []PathSegment) string {
+	keys := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment.Kind == PathSegmentKey {
+			keys = append(keys, segment.Key)
+		}
+	}
+	return strings.Join(keys, ".")
+}
+
+// GetPathNode returns the node at the path expression (see ParsePath)
+func GetPathNode(node *yaml.Node, path string) (*yaml.Node, error) {
+	segments, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := unwrapNode(node)
+	for _, segment := range segments {
+		if current == nil {
+			break
+		}
+		current = getPathChild(current, segment)
+	}
+	if current == nil {
+		return nil, errors.Wrapf(ErrNodeNotFound, "%q", path)
+	}
+	return current, nil
+}
+
+// SetPathNode sets the value of the node at the path expression (see ParsePath) and returns true if the node
+// has changed. The missing nodes of the path are created, including the sequence elements selected by the
+// value of a key. The mapping values, and the sequence values with a patch strategy, are merged as per the
+// patch strategies (see DeleteNodes and MergeNodes) unless the patch strategy of the path is "replace", any
+// other value is replaced. The comments of the replaced node are kept.
+func SetPathNode(node *yaml.Node, path string, value *yaml.Node, opts ...PatchStrategyOpts) (bool, error) {
+	if node == nil || value == nil {
+		return false, errors.New("node and value cannot be nil")
+	}
+	segments, err := ParsePath(path)
+	if err != nil {
+		return false, err
+	}
+	value = unwrapNode(value)
+	if value == nil {
+		return false, errors.New("value cannot be an empty document")
+	}
+
+	options := &PatchStrategyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	current := documentContent(node)
+	created := false
+	for i, segment := range segments {
+		kind := value.Kind
+		if i+1 < len(segments) {
+			kind = yaml.MappingNode
+			if segments[i+1].Kind != PathSegmentKey {
+				kind = yaml.SequenceNode
+			}
+		}
+		current, created, err = getOrCreatePathChild(current, segment, kind)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to set %q", path)
+		}
+	}
+	if created && segments[len(segments)-1].Kind == PathSegmentKey {
+		*current = *value
+		return true, nil
+	}
+
+	key := PathPatchStrategyKey(segments)
+	if options.Key != "" {
+		key = options.Key + "." + key
+	}
+	strategy, hasStrategy := LookupPatchStrategy(options.PatchStrategies, key)
+	mergeable := current.Kind == value.Kind &&
+		(current.Kind == yaml.MappingNode || (current.Kind == yaml.SequenceNode && hasStrategy))
+	if mergeable && strategy.Strategy != PatchStrategyReplace {
+		patchStrategyOpts := []PatchStrategyOpts{WithPatchStrategyKey(key), WithPatchStrategies(options.PatchStrategies)}
+		if _, err := DeleteNodes(value, current, patchStrategyOpts...); err != nil {
+			return false, err
+		}
+		persist, err := MergeNodes(value, current, patchStrategyOpts...)
+		return persist || created, err
+	}
+	if nodesEqual(current, value) {
+		return false, nil
+	}
+	replaceNode(current, value)
+	return true, nil
+}
+
+// getPathChild returns the child of the node selected by the segment, nil if it is not found
+func getPathChild(node *yaml.Node, segment PathSegment) *yaml.Node {
+	switch {
+	case segment.Kind == PathSegmentKey && node.Kind == yaml.MappingNode:
+		if idx := GetNodeIndex(node.Content, segment.Key); idx != -1 {
+			return unwrapNode(node.Content[idx])
+		}
+	case segment.Kind == PathSegmentIndex && node.Kind == yaml.SequenceNode:
+		if segment.Index < len(node.Content) {
+			return unwrapNode(node.Content[segment.Index])
+		}
+	case segment.Kind == PathSegmentMatch && node.Kind == yaml.SequenceNode:
+		for _, element := range node.Content {
+			element = unwrapNode(element)
+			if element == nil || element.Kind != yaml.MappingNode {
+				continue
+			}
+			if idx := GetNodeIndex(element.Content, segment.Key); idx != -1 && element.Content[idx].Value == segment.Value {
+				return element
+			}
+		}
+	}
+	return nil
+}
+
+// getOrCreatePathChild returns the child of the node selected by the segment and true if the child has been
+// created. A missing child selected by key is created with the kind and a missing sequence element selected
+// by the value of a key is created as a mapping node with the key.
+func getOrCreatePathChild(node *yaml.Node, segment PathSegment, kind yaml.Kind) (*yaml.Node, bool, error) {
+	if child := getPathChild(node, segment); child != nil {
+		return child, false, nil
+	}
+	switch segment.Kind {
+	case PathSegmentKey:
+		if node.Kind != yaml.MappingNode {
+			return nil, false, errors.Errorf("cannot get key %q of a node which is not a mapping", segment.Key)
+		}
+		child := &yaml.Node{Kind: kind}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: segment.Key}, child)
+		return child, true, nil
+	case PathSegmentMatch:
+		if node.Kind != yaml.SequenceNode {
+			return nil, false, errors.Errorf("cannot select %s of a node which is not a sequence", segment)
+		}
+		child := &yaml.Node{Kind: yaml.MappingNode, Content: CreateScalarNode(segment.Key, segment.Value)}
+		node.Content = append(node.Content, child)
+		return child, true, nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		return nil, false, errors.Errorf("cannot select %s of a node which is not a sequence", segment)
+	}
+	return nil, false, errors.Errorf("index %d out of range", segment.Index)
+}
+
+// replaceNode replaces the node with the value, keeping the comments of the node
+func replaceNode(node, value *yaml.Node) {
+	headComment, lineComment, footComment := node.HeadComment, node.LineComment, node.FootComment
+	*node = *value
+	if node.HeadComment == "" {
+		node.HeadComment = headComment
+	}
+	if node.LineComment == "" {
+		node.LineComment = lineComment
+	}
+	if node.FootComment == "" {
+		node.FootComment = footComment
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package nodeutils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected []PathSegment
+		err      string
+	}{
+		{
+			name: "keys and selectors",
+			path: "contexts[name=prod].clusterOpts.context",
+			expected: []PathSegment{
+				{Kind: PathSegmentKey, Key: "contexts"},
+				{Kind: PathSegmentMatch, Key: "name", Value: "prod"},
+				{Kind: PathSegmentKey, Key: "clusterOpts"},
+				{Kind: PathSegmentKey, Key: "context"},
+			},
+		},
+		{
+			name: "consecutive selectors",
+			path: "a[0][b=]",
+			expected: []PathSegment{
+				{Kind: PathSegmentKey, Key: "a"},
+				{Kind: PathSegmentIndex, Index: 0},
+				{Kind: PathSegmentMatch, Key: "b", Value: ""},
+			},
+		},
+		{name: "empty path", path: "", err: "path cannot be empty"},
+		{name: "leading selector", path: "[0]", err: `invalid path "[0]": path must start with a key`},
+		{name: "empty key", path: "a..b", err: `invalid path "a..b": empty key at offset 2`},
+		{name: "trailing dot", path: "a.", err: `invalid path "a.": empty key at offset 2`},
+		{name: "missing bracket", path: "a[0", err: `invalid path "a[0": missing ']'`},
+		{name: "key after selector", path: "a[0]b", err: `invalid path "a[0]b": unexpected 'b' at offset 4`},
+		{name: "invalid index", path: "a[-1]", err: `invalid path "a[-1]": invalid selector "-1"`},
+		{name: "empty selector key", path: "a[=b]", err: `invalid path "a[=b]": empty key in selector "=b"`},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			segments, err := ParsePath(spec.path)
+			if spec.err != "" {
+				assert.EqualError(t, err, spec.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expected, segments)
+		})
+	}
+}
+
+func TestPathPatchStrategyKey(t *testing.T) {
+	segments, err := ParsePath("contexts[name=prod].clusterOpts[0].context")
+	require.NoError(t, err)
+	assert.Equal(t, "contexts.clusterOpts.context", PathPatchStrategyKey(segments))
+}
+
+func TestGetPathNode(t *testing.T) {
+	node := parseNode(t, `contexts:
+  - name: dev
+    clusterOpts:
+      context: dev-ctx
+  - name: prod
+    clusterOpts:
+      context: prod-ctx
+`)
+	tests := []struct {
+		path     string
+		expected string
+		err      string
+	}{
+		{path: "contexts[name=prod].clusterOpts.context", expected: "prod-ctx"},
+		{path: "contexts[0].clusterOpts", expected: "context: dev-ctx"},
+		{path: "contexts[name=test]", err: `"contexts[name=test]": node not found`},
+		{path: "contexts[2]", err: `"contexts[2]": node not found`},
+		{path: "contexts.name", err: `"contexts.name": node not found`},
+		{path: "contexts[", err: `invalid path "contexts[": missing ']'`},
+	}
+	for _, spec := range tests {
+		t.Run(spec.path, func(t *testing.T) {
+			value, err := GetPathNode(node, spec.path)
+			if spec.err != "" {
+				assert.EqualError(t, err, spec.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expected, marshalNode(t, value))
+		})
+	}
+}
+
+func TestSetPathNode(t *testing.T) {
+	cfg := `# contexts
+contexts:
+  - name: prod
+    target: k8s # target of prod
+    additionalMetadata:
+      a: b
+    clusterOpts:
+      context: prod-ctx
+`
+	tests := []struct {
+		name            string
+		path            string
+		value           string
+		patchStrategies map[string]string
+		persist         bool
+		expected        string
+		err             string
+	}{
+		{
+			name:    "replace a scalar and keep its comment",
+			path:    "contexts[name=prod].target",
+			value:   "tmc",
+			persist: true,
+			expected: `# contexts
+contexts:
+    - name: prod
+      target: tmc # target of prod
+      additionalMetadata:
+        a: b
+      clusterOpts:
+        context: prod-ctx
+`,
+		},
+		{
+			name:    "set an equal value",
+			path:    "contexts[0].target",
+			value:   "k8s",
+			persist: false,
+		},
+		{
+			name:    "create the missing nodes",
+			path:    "contexts[name=dev].clusterOpts.context",
+			value:   "dev-ctx",
+			persist: true,
+			expected: `# contexts
+contexts:
+    - name: prod
+      target: k8s # target of prod
+      additionalMetadata:
+        a: b
+      clusterOpts:
+        context: prod-ctx
+    - name: dev
+      clusterOpts:
+        context: dev-ctx
+`,
+		},
+		{
+			name:    "merge a map",
+			path:    "contexts[name=prod].additionalMetadata",
+			value:   "c: d",
+			persist: true,
+			expected: `# contexts
+contexts:
+    - name: prod
+      target: k8s # target of prod
+      additionalMetadata:
+        a: b
+        c: d
+      clusterOpts:
+        context: prod-ctx
+`,
+		},
+		{
+			name:            "replace a map as per the patch strategy",
+			path:            "contexts[name=prod].additionalMetadata",
+			value:           "c: d",
+			patchStrategies: map[string]string{"contexts.additionalMetadata": "replace"},
+			persist:         true,
+			expected: `# contexts
+contexts:
+    - name: prod
+      target: k8s # target of prod
+      additionalMetadata:
+        c: d
+      clusterOpts:
+        context: prod-ctx
+`,
+		},
+		{
+			name:            "replace a nested map as per the patch strategy",
+			path:            "contexts[name=prod]",
+			value:           "clusterOpts:\n  path: /tmp/kubeconfig",
+			patchStrategies: map[string]string{"contexts.clusterOpts": "replace"},
+			persist:         true,
+			expected: `# contexts
+contexts:
+    - name: prod
+      target: k8s # target of prod
+      additionalMetadata:
+        a: b
+      clusterOpts:
+        path: /tmp/kubeconfig
+`,
+		},
+		{
+			name:  "index out of range",
+			path:  "contexts[1].target",
+			value: "k8s",
+			err:   `failed to set "contexts[1].target": index 1 out of range`,
+		},
+		{
+			name:  "key of a sequence",
+			path:  "contexts.target",
+			value: "k8s",
+			err:   `failed to set "contexts.target": cannot get key "target" of a node which is not a mapping`,
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			node := parseNode(t, cfg)
+			persist, err := SetPathNode(node, spec.path, parseNode(t, spec.value), WithPatchStrategies(spec.patchStrategies))
+			if spec.err != "" {
+				assert.EqualError(t, err, spec.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.persist, persist)
+			if spec.persist {
+				assert.Equal(t, spec.expected, marshalDocument(t, node))
+			}
+		})
+	}
+}
@@ -205,3 +205,73 @@ func TestIntegrationWithReplacePatchStrategy(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedCfg2, string(file))
 }
+
+func TestIntegrationWithAppendPatchStrategy(t *testing.T) {
+	metadata := `configMetadata:
+  patchStrategy:
+    contexts.additionalMetadata.tags: append
+    contexts.additionalMetadata.labels: prepend
+    servers.globalOpts.auth.permissions: append
+`
+	_, cleanUp := setupTestConfig(t, &CfgTestData{cfgMetadata: metadata})
+
+	defer func() {
+		cleanUp()
+	}()
+
+	ctx := &configtypes.Context{
+		Name:   "test-mc",
+		Target: configtypes.TargetK8s,
+		AdditionalMetadata: map[string]interface{}{
+			"tags":   []interface{}{"a", "b"},
+			"labels": []interface{}{"x"},
+		},
+		DiscoverySources: []configtypes.PluginDiscovery{
+			{OCI: &configtypes.OCIDiscovery{Name: "test", Image: "image"}},
+		},
+	}
+	server := &configtypes.Server{
+		Name:       "test-server",
+		Type:       configtypes.GlobalServerType,
+		GlobalOpts: &configtypes.GlobalServer{Auth: configtypes.GlobalServerAuth{Permissions: []string{"read"}}},
+		DiscoverySources: []configtypes.PluginDiscovery{
+			{OCI: &configtypes.OCIDiscovery{Name: "test", Image: "image"}},
+		},
+	}
+
+	// Setting the same objects again does not duplicate the list elements
+	for i := 0; i < 2; i++ {
+		err := SetContext(ctx, false)
+		assert.NoError(t, err)
+		err = SetServer(server, false)
+		assert.NoError(t, err)
+	}
+	actualCtx, err := GetContext("test-mc")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b"}, actualCtx.AdditionalMetadata["tags"])
+	assert.Equal(t, []interface{}{"x"}, actualCtx.AdditionalMetadata["labels"])
+	actualServer, err := GetServer("test-server")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read"}, actualServer.GlobalOpts.Auth.Permissions)
+
+	// The new list elements are added as per the patch strategy, also when the discovery sources are updated
+	ctx.AdditionalMetadata["tags"] = []interface{}{"a", "b", "c"}
+	ctx.AdditionalMetadata["labels"] = []interface{}{"y", "x"}
+	ctx.DiscoverySources[0].OCI.Image = "updated-image"
+	err = SetContext(ctx, false)
+	assert.NoError(t, err)
+	server.GlobalOpts.Auth.Permissions = []string{"read", "write"}
+	server.DiscoverySources[0].OCI.Image = "updated-image"
+	err = SetServer(server, false)
+	assert.NoError(t, err)
+
+	actualCtx, err = GetContext("test-mc")
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, actualCtx.AdditionalMetadata["tags"])
+	assert.Equal(t, []interface{}{"y", "x"}, actualCtx.AdditionalMetadata["labels"])
+	assert.Equal(t, "updated-image", actualCtx.DiscoverySources[0].OCI.Image)
+	actualServer, err = GetServer("test-server")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"read", "write"}, actualServer.GlobalOpts.Auth.Permissions)
+	assert.Equal(t, "updated-image", actualServer.DiscoverySources[0].OCI.Image)
+}
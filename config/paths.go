@@ -0,0 +1,189 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+)
+
+const (
+	// EnvConfigDirKey is the environment variable overriding the directory in which the tanzu configuration is stored
+	EnvConfigDirKey = "TANZU_CONFIG_DIR"
+	// EnvStateDirKey is the environment variable overriding the directory in which the tanzu state (logs, config snapshots, crash reports) is stored
+	EnvStateDirKey = "TANZU_STATE_DIR"
+	// EnvCacheDirKey is the environment variable overriding the directory in which the tanzu caches are stored
+	EnvCacheDirKey = "TANZU_CACHE_DIR"
+
+	// EnvXDGConfigHome is the environment variable specifying the base directory of user specific configuration
+	EnvXDGConfigHome = "XDG_CONFIG_HOME"
+	// EnvXDGStateHome is the environment variable specifying the base directory of user specific state
+	EnvXDGStateHome = "XDG_STATE_HOME"
+	// EnvXDGCacheHome is the environment variable specifying the base directory of user specific caches
+	EnvXDGCacheHome = "XDG_CACHE_HOME"
+
+	// xdgDirName is the name of the tanzu directory in the XDG base directories
+	xdgDirName = "tanzu"
+	// cacheDirName is the name of the cache directory in the local tanzu directory
+	cacheDirName = "cache"
+	// logsDirName is the name of the logs directory in the state directory
+	logsDirName = "logs"
+	// crashReportsDirName is the name of the crash reports directory in the state directory
+	crashReportsDirName = "crash-reports"
+)
+
+// DirKind is the kind of a tanzu directory
+type DirKind string
+
+const (
+	// DirKindConfig is the directory of the tanzu configuration files and plugin owned configuration
+	DirKindConfig DirKind = "config"
+	// DirKindState is the directory of the tanzu state e.g. logs, config snapshots and crash reports
+	DirKindState DirKind = "state"
+	// DirKindCache is the directory of the tanzu caches
+	DirKindCache DirKind = "cache"
+)
+
+// dirResolver resolves a tanzu directory from, in order of precedence, the override environment variable,
+// the XDG base directory environment variable and the default directory
+type dirResolver struct {
+	overrideEnv string
+	xdgEnv      string
+	defaultDir  func() (string, error)
+}
+
+// getDirResolver returns the resolver of the directory kind
+func getDirResolver(kind DirKind) (dirResolver, bool) {
+	switch kind {
+	case DirKindConfig:
+		return dirResolver{overrideEnv: EnvConfigDirKey, xdgEnv: EnvXDGConfigHome, defaultDir: defaultLocalDir}, true
+	case DirKindState:
+		return dirResolver{overrideEnv: EnvStateDirKey, xdgEnv: EnvXDGStateHome, defaultDir: LocalDir}, true
+	case DirKindCache:
+		return dirResolver{overrideEnv: EnvCacheDirKey, xdgEnv: EnvXDGCacheHome, defaultDir: defaultCacheDir}, true
+	}
+	return dirResolver{}, false
+}
+
+// ResolveDir returns the tanzu directory of the kind. The directory is resolved from, in order of precedence:
+//   - the override environment variable (TANZU_CONFIG_DIR, TANZU_STATE_DIR, TANZU_CACHE_DIR)
+//   - the XDG base directory environment variable (XDG_CONFIG_HOME, XDG_STATE_HOME, XDG_CACHE_HOME) suffixed with "tanzu"
+//   - the default directory: ~/.config/tanzu for the configuration and the state, ~/.config/tanzu/cache for the caches
+//
+// The configuration of ~/.config/tanzu is not used once the configuration directory is relocated, see MigrateLocalDir.
+// Relative paths in the environment variables are ignored as required by the XDG base directory specification.
+func ResolveDir(kind DirKind) (string, error) {
+	resolver, ok := getDirResolver(kind)
+	if !ok {
+		return "", errors.Errorf("unknown directory kind %q", kind)
+	}
+	if dir, ok := explicitDir(resolver); ok {
+		return dir, nil
+	}
+	return resolver.defaultDir()
+}
+
+// explicitDir returns the directory specified with the override or the XDG base directory environment variable
+func explicitDir(resolver dirResolver) (string, bool) {
+	if dir := os.Getenv(resolver.overrideEnv); filepath.IsAbs(dir) {
+		return dir, true
+	}
+	if xdgDir := os.Getenv(resolver.xdgEnv); filepath.IsAbs(xdgDir) {
+		return filepath.Join(xdgDir, xdgDirName), true
+	}
+	return "", false
+}
+
+// LocalStateDir returns the directory in which the tanzu state e.g. logs, config snapshots and crash reports is stored
+func LocalStateDir() (string, error) {
+	return ResolveDir(DirKindState)
+}
+
+// LocalCacheDir returns the directory in which the tanzu caches are stored
+func LocalCacheDir() (string, error) {
+	return ResolveDir(DirKindCache)
+}
+
+// GetTanzuLogsDir returns the directory in which the tanzu log files are stored
+func GetTanzuLogsDir() (string, error) {
+	stateDir, err := LocalStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, logsDirName), nil
+}
+
+// GetTanzuCrashReportsDir returns the directory in which the crash reports of the plugins are stored
+func GetTanzuCrashReportsDir() (string, error) {
+	stateDir, err := LocalStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, crashReportsDirName), nil
+}
+
+// defaultLocalDir returns the default directory in which the tanzu configuration is stored
+func defaultLocalDir() (string, error) {
+	return localDirPath(LocalDirName)
+}
+
+// defaultCacheDir returns the default directory in which the tanzu caches are stored
+func defaultCacheDir() (string, error) {
+	localDir, err := LocalDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(localDir, cacheDirName), nil
+}
+
+// statePath returns the path of the state file (e.g. config snapshots) of the config file. The state file is stored
+// in the state directory if it is specified with an environment variable and next to the config file otherwise.
+// Note that the lock files are always stored next to the config file, as the processes using older versions of the
+// runtime lock the config file with the lock files next to it.
+func statePath(cfgPath, name string) string {
+	resolver, _ := getDirResolver(DirKindState)
+	if stateDir, ok := explicitDir(resolver); ok {
+		return filepath.Join(stateDir, name)
+	}
+	return filepath.Join(filepath.Dir(cfgPath), name)
+}
+
+// MigrateLocalDir copies the configuration files from the default directory (~/.config/tanzu) to the
+// configuration directory specified with TANZU_CONFIG_DIR or XDG_CONFIG_HOME. This is a no-op if the
+// configuration directory is the default directory, if the default directory does not exist or if the
+// configuration directory already exists.
+// The CLI is expected to call it before accessing the configuration once the configuration directory is relocated.
+func MigrateLocalDir() error {
+	oldDir, err := defaultLocalDir()
+	if err != nil {
+		return err
+	}
+	newDir, err := LocalDir()
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(oldDir) == filepath.Clean(newDir) {
+		return nil
+	}
+	oldDirExists, err := fileExists(oldDir)
+	if err != nil {
+		return err
+	}
+	newDirExists, err := fileExists(newDir)
+	if err != nil {
+		return err
+	}
+	if !oldDirExists || newDirExists {
+		return nil
+	}
+	if err := copyDir(oldDir, newDir); err != nil {
+		return errors.Wrapf(err, "failed to migrate the configuration from %s to %s", oldDir, newDir)
+	}
+	log.Warningf("Configuration is now stored in %s. Configuration directory %s is no longer used and can be removed.", newDir, oldDir)
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDir(t *testing.T) {
+	home := t.TempDir()
+	defaultDir := filepath.Join(home, LocalDirName)
+
+	tests := []struct {
+		name     string
+		envs     map[string]string
+		expected map[DirKind]string
+	}{
+		{
+			name: "should default to the local dir",
+			expected: map[DirKind]string{
+				DirKindConfig: defaultDir,
+				DirKindState:  defaultDir,
+				DirKindCache:  filepath.Join(defaultDir, "cache"),
+			},
+		},
+		{
+			name: "should honor the XDG base directories",
+			envs: map[string]string{
+				EnvXDGConfigHome: filepath.Join(home, "xdg-config"),
+				EnvXDGStateHome:  filepath.Join(home, "xdg-state"),
+				EnvXDGCacheHome:  filepath.Join(home, "xdg-cache"),
+			},
+			expected: map[DirKind]string{
+				DirKindConfig: filepath.Join(home, "xdg-config", "tanzu"),
+				DirKindState:  filepath.Join(home, "xdg-state", "tanzu"),
+				DirKindCache:  filepath.Join(home, "xdg-cache", "tanzu"),
+			},
+		},
+		{
+			name: "should prefer the override env vars to the XDG base directories",
+			envs: map[string]string{
+				EnvXDGConfigHome: filepath.Join(home, "xdg-config"),
+				EnvXDGStateHome:  filepath.Join(home, "xdg-state"),
+				EnvXDGCacheHome:  filepath.Join(home, "xdg-cache"),
+				EnvConfigDirKey:  filepath.Join(home, "config"),
+				EnvStateDirKey:   filepath.Join(home, "state"),
+				EnvCacheDirKey:   filepath.Join(home, "cache"),
+			},
+			expected: map[DirKind]string{
+				DirKindConfig: filepath.Join(home, "config"),
+				DirKindState:  filepath.Join(home, "state"),
+				DirKindCache:  filepath.Join(home, "cache"),
+			},
+		},
+		{
+			name: "should derive the state and cache dirs from the relocated config dir",
+			envs: map[string]string{
+				EnvXDGConfigHome: filepath.Join(home, "xdg-config"),
+			},
+			expected: map[DirKind]string{
+				DirKindConfig: filepath.Join(home, "xdg-config", "tanzu"),
+				DirKindState:  filepath.Join(home, "xdg-config", "tanzu"),
+				DirKindCache:  filepath.Join(home, "xdg-config", "tanzu", "cache"),
+			},
+		},
+		{
+			name: "should ignore relative paths",
+			envs: map[string]string{
+				EnvXDGConfigHome: "xdg-config",
+				EnvStateDirKey:   "state",
+			},
+			expected: map[DirKind]string{
+				DirKindConfig: defaultDir,
+				DirKindState:  defaultDir,
+				DirKindCache:  filepath.Join(defaultDir, "cache"),
+			},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			t.Setenv("USERPROFILE", home)
+			for _, key := range []string{EnvConfigDirKey, EnvStateDirKey, EnvCacheDirKey, EnvXDGConfigHome, EnvXDGStateHome, EnvXDGCacheHome} {
+				t.Setenv(key, spec.envs[key])
+			}
+
+			for kind, expected := range spec.expected {
+				dir, err := ResolveDir(kind)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, dir, kind)
+			}
+		})
+	}
+
+	_, err := ResolveDir("unknown")
+	assert.EqualError(t, err, `unknown directory kind "unknown"`)
+}
+
+func TestConfigPathsWithXDGConfigHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	xdgConfigHome := t.TempDir()
+	t.Setenv(EnvXDGConfigHome, xdgConfigHome)
+	t.Setenv(EnvConfigKey, "")
+	os.Unsetenv(EnvConfigKey)
+
+	tanzuDir := filepath.Join(xdgConfigHome, "tanzu")
+	cfgPath, err := ClientConfigPath()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tanzuDir, ConfigName), cfgPath)
+
+	pluginDir, err := GetTanzuPluginConfigDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tanzuDir, PluginsBaseDir), pluginDir)
+
+	logsDir, err := GetTanzuLogsDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tanzuDir, "logs"), logsDir)
+
+	crashReportsDir, err := GetTanzuCrashReportsDir()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(tanzuDir, "crash-reports"), crashReportsDir)
+}
+
+func TestStatePath(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(EnvStateDirKey, "")
+	t.Setenv(EnvXDGStateHome, "")
+	assert.Equal(t, filepath.Join(filepath.Dir(cfgPath), snapshotsDirName), statePath(cfgPath, snapshotsDirName))
+
+	stateHome := t.TempDir()
+	t.Setenv(EnvXDGStateHome, stateHome)
+	assert.Equal(t, filepath.Join(stateHome, "tanzu", snapshotsDirName), statePath(cfgPath, snapshotsDirName))
+}
+
+func TestMigrateLocalDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv(EnvConfigDirKey, "")
+	t.Setenv(EnvConfigKey, "")
+	os.Unsetenv(EnvConfigKey)
+
+	// No-op if the config dir is not relocated
+	t.Setenv(EnvXDGConfigHome, "")
+	assert.NoError(t, MigrateLocalDir())
+
+	oldDir := filepath.Join(home, LocalDirName)
+	require.NoError(t, os.MkdirAll(filepath.Join(oldDir, PluginsBaseDir, "my-plugin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, ConfigName), []byte("kind: ClientConfig\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, PluginsBaseDir, "my-plugin", "config.yaml"), []byte("key: value\n"), 0o600))
+
+	// XDG_CONFIG_HOME is used even if the old config dir exists
+	xdgConfigHome := filepath.Join(home, "xdg-config")
+	t.Setenv(EnvXDGConfigHome, xdgConfigHome)
+	newDir := filepath.Join(xdgConfigHome, "tanzu")
+	cfgPath, err := ClientConfigPath()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(newDir, ConfigName), cfgPath)
+
+	assert.NoError(t, MigrateLocalDir())
+	data, err := os.ReadFile(filepath.Join(newDir, ConfigName))
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: ClientConfig\n", string(data))
+	data, err = os.ReadFile(filepath.Join(newDir, PluginsBaseDir, "my-plugin", "config.yaml"))
+	assert.NoError(t, err)
+	assert.Equal(t, "key: value\n", string(data))
+
+	// No-op if the new config dir already exists
+	require.NoError(t, os.WriteFile(filepath.Join(oldDir, ConfigName), []byte("kind: Updated\n"), 0o600))
+	assert.NoError(t, MigrateLocalDir())
+	data, err = os.ReadFile(filepath.Join(newDir, ConfigName))
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: ClientConfig\n", string(data))
+
+	// The directory specified with TANZU_CONFIG_DIR takes precedence
+	configDir := filepath.Join(home, "config")
+	t.Setenv(EnvConfigDirKey, configDir)
+	assert.NoError(t, MigrateLocalDir())
+	data, err = os.ReadFile(filepath.Join(configDir, ConfigName))
+	assert.NoError(t, err)
+	assert.Equal(t, "kind: Updated\n", string(data))
+}
@@ -6,6 +6,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -32,3 +33,29 @@ func GetTanzuPluginConfigDir() (string, error) {
 
 	return pluginsBaseDir, nil
 }
+
+// GetTanzuPluginCacheDir retrieves the base directory of the plugin owned caches.
+// <LocalCacheDir>/plugins e.g. $XDG_CACHE_HOME/tanzu/plugins or .config/tanzu/cache/plugins
+func GetTanzuPluginCacheDir() (string, error) {
+	cacheDir, err := LocalCacheDir()
+	if err != nil {
+		return "", errors.Wrap(err, "could not find local tanzu cache dir for OS")
+	}
+	return filepath.Join(cacheDir, PluginsBaseDir), nil
+}
+
+// GetPluginCacheDir retrieves the cache directory of the plugin and creates it if it doesn't exist
+func GetPluginCacheDir(plugin string) (string, error) {
+	if plugin == "" || plugin == "." || plugin == ".." || strings.ContainsAny(plugin, `/\`) {
+		return "", errors.Errorf("invalid plugin name %q", plugin)
+	}
+	cacheDir, err := GetTanzuPluginCacheDir()
+	if err != nil {
+		return "", err
+	}
+	pluginCacheDir := filepath.Join(cacheDir, plugin)
+	if err := os.MkdirAll(pluginCacheDir, 0o700); err != nil {
+		return "", errors.Wrapf(err, "could not make the cache directory of plugin %q", plugin)
+	}
+	return pluginCacheDir, nil
+}
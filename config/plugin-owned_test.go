@@ -4,6 +4,7 @@
 package config
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -28,3 +29,33 @@ func TestGetTanzuPluginConfigDir(t *testing.T) {
 
 	assert.Contains(t, dir, expectedPath)
 }
+
+func TestGetPluginCacheDir(t *testing.T) {
+	func() {
+		LocalDirName = ".config2/tanzu"
+	}()
+	defer func() {
+		cleanupDir(LocalDirName)
+	}()
+
+	dir, err := GetPluginCacheDir("my-plugin")
+	require.NoError(t, err)
+	assert.Contains(t, dir, filepath.Join(".config2", "tanzu", "cache", "plugins", "my-plugin"))
+	assert.DirExists(t, dir)
+
+	xdgCacheHome := t.TempDir()
+	t.Setenv(EnvXDGCacheHome, xdgCacheHome)
+	dir, err = GetPluginCacheDir("my-plugin")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(xdgCacheHome, "tanzu", "plugins", "my-plugin"), dir)
+	assert.DirExists(t, dir)
+
+	// A relative XDG_CACHE_HOME is ignored
+	t.Setenv(EnvXDGCacheHome, "relative/cache")
+	dir, err = GetTanzuPluginCacheDir()
+	require.NoError(t, err)
+	assert.Contains(t, dir, filepath.Join(".config2", "tanzu", "cache", "plugins"))
+
+	_, err = GetPluginCacheDir("../my-plugin")
+	assert.EqualError(t, err, `invalid plugin name "../my-plugin"`)
+}
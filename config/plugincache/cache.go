@@ -0,0 +1,323 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package plugincache provides a size limited cache scoped to a plugin, stored in the
+// cache directory of the plugin (see config.GetPluginCacheDir). The entries are written
+// atomically and the least recently used entries are evicted when the cache exceeds its max size.
+package plugincache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/fslock"
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/internal/atomicfile"
+)
+
+const (
+	// DefaultMaxSize is the default max size in bytes of the cache of a plugin
+	DefaultMaxSize int64 = 64 * 1024 * 1024
+
+	entryFileExt  = ".entry"
+	lockFileName  = ".cache.lock"
+	entryFileMode = 0o600
+)
+
+var (
+	// lockTimeout is the time waiting on the file lock of the cache, overridden in tests
+	lockTimeout = config.DefaultLockTimeout
+	// timeNow returns the current time, overridden in tests
+	timeNow = time.Now
+)
+
+// CacheOptions are the options of the plugin cache
+type CacheOptions struct {
+	// TTL is the default time to live of the entries. The entries never expire if 0
+	TTL time.Duration
+	// MaxSize is the max size in bytes of the cache. Defaults to DefaultMaxSize
+	MaxSize int64
+}
+
+type CacheOption func(opts *CacheOptions)
+
+// WithTTL sets the default time to live of the cache entries
+func WithTTL(ttl time.Duration) CacheOption {
+	return func(opts *CacheOptions) {
+		opts.TTL = ttl
+	}
+}
+
+// WithMaxSize sets the max size in bytes of the cache
+func WithMaxSize(maxSize int64) CacheOption {
+	return func(opts *CacheOptions) {
+		opts.MaxSize = maxSize
+	}
+}
+
+// Cache is the cache of a plugin
+type Cache struct {
+	plugin  string
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+}
+
+// entryHeader is the first line of an entry file and is followed by the cached data
+type entryHeader struct {
+	Key     string     `json:"key"`
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+// New returns the cache of the plugin
+func New(plugin string, opts ...CacheOption) (*Cache, error) {
+	options := &CacheOptions{MaxSize: DefaultMaxSize}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.TTL < 0 {
+		return nil, errors.Errorf("invalid ttl %v, cannot be negative", options.TTL)
+	}
+	if options.MaxSize <= 0 {
+		return nil, errors.Errorf("invalid max size %d, must be greater than 0", options.MaxSize)
+	}
+
+	dir, err := config.GetPluginCacheDir(plugin)
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{plugin: plugin, dir: dir, ttl: options.TTL, maxSize: options.MaxSize}, nil
+}
+
+// Clear removes the cache of the plugin
+func Clear(plugin string) error {
+	dir, err := config.GetPluginCacheDir(plugin)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrapf(err, "failed to clear the cache of plugin %q", plugin)
+	}
+	return nil
+}
+
+// ClearAll removes the caches of all the plugins
+func ClearAll() error {
+	dir, err := config.GetTanzuPluginCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrap(err, "failed to clear the plugin caches")
+	}
+	return nil
+}
+
+// Dir returns the directory of the cache
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Get returns the data cached for the key and true, or false if the key is not cached or has expired
+func (c *Cache) Get(key string) ([]byte, bool, error) {
+	path := c.entryPath(key)
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read the cache entry %q", key)
+	}
+
+	line, data, found := bytes.Cut(content, []byte("\n"))
+	header := &entryHeader{}
+	if !found || json.Unmarshal(line, header) != nil || header.Key != key {
+		// Treat corrupted entries as missing, they are replaced on the next Put
+		return nil, false, nil
+	}
+	now := timeNow()
+	if header.Expires != nil && !now.Before(*header.Expires) {
+		return nil, false, nil
+	}
+
+	// Record the access for the LRU eviction. The access time is not reliable on all
+	// file systems, so the modification time is used instead
+	_ = os.Chtimes(path, now, now)
+	return data, true, nil
+}
+
+// Put caches the data for the key with the default time to live of the cache
+func (c *Cache) Put(key string, data []byte) error {
+	return c.PutWithTTL(key, data, c.ttl)
+}
+
+// PutWithTTL caches the data for the key with the time to live. The entry never expires if ttl is 0.
+// The least recently used entries are evicted if the cache exceeds its max size.
+func (c *Cache) PutWithTTL(key string, data []byte, ttl time.Duration) error {
+	if key == "" {
+		return errors.New("key cannot be empty")
+	}
+	if ttl < 0 {
+		return errors.Errorf("invalid ttl %v, cannot be negative", ttl)
+	}
+
+	header := &entryHeader{Key: key}
+	if ttl > 0 {
+		expires := timeNow().Add(ttl)
+		header.Expires = &expires
+	}
+	line, err := json.Marshal(header)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the cache entry %q", key)
+	}
+	content := make([]byte, 0, len(line)+1+len(data))
+	content = append(append(append(content, line...), '\n'), data...)
+	if int64(len(content)) > c.maxSize {
+		return errors.Errorf("cache entry %q of %d bytes exceeds the max size %d of the cache", key, len(content), c.maxSize)
+	}
+
+	return c.withLock(func() error {
+		if err := atomicfile.WriteFile(c.entryPath(key), content, entryFileMode); err != nil {
+			return errors.Wrapf(err, "failed to write the cache entry %q", key)
+		}
+		return c.evict()
+	})
+}
+
+// Delete removes the key from the cache
+func (c *Cache) Delete(key string) error {
+	return c.withLock(func() error {
+		if err := os.Remove(c.entryPath(key)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to delete the cache entry %q", key)
+		}
+		return nil
+	})
+}
+
+// Clear removes all the entries of the cache
+func (c *Cache) Clear() error {
+	return c.withLock(func() error {
+		entries, err := c.listEntries()
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrapf(err, "failed to clear the cache of plugin %q", c.plugin)
+			}
+		}
+		return nil
+	})
+}
+
+// withLock runs the function holding the file lock of the cache
+func (c *Cache) withLock(f func() error) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return errors.Wrapf(err, "could not make the cache directory of plugin %q", c.plugin)
+	}
+	lock := fslock.New(filepath.Join(c.dir, lockFileName))
+	if err := lock.LockWithTimeout(lockTimeout); err != nil {
+		return errors.Wrapf(err, "cannot acquire lock for the cache of plugin %q", c.plugin)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+	return f()
+}
+
+type entryFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// evict removes the expired entries, then the least recently used entries until the cache fits its max size
+func (c *Cache) evict() error {
+	entries, err := c.listEntries()
+	if err != nil {
+		return err
+	}
+
+	now := timeNow()
+	var size int64
+	live := entries[:0]
+	for _, e := range entries {
+		if isExpired(e.path, now) {
+			if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "failed to evict the expired cache entry")
+			}
+			continue
+		}
+		size += e.size
+		live = append(live, e)
+	}
+
+	sort.SliceStable(live, func(i, j int) bool {
+		return live[i].modTime.Before(live[j].modTime)
+	})
+	for _, e := range live {
+		if size <= c.maxSize {
+			break
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to evict the least recently used cache entry")
+		}
+		size -= e.size
+	}
+	return nil
+}
+
+// listEntries returns the entry files of the cache
+func (c *Cache) listEntries() ([]entryFile, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the cache directory of plugin %q", c.plugin)
+	}
+	var entries []entryFile
+	for _, d := range dirEntries {
+		if d.IsDir() || !strings.HasSuffix(d.Name(), entryFileExt) {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			// The entry has been removed meanwhile
+			continue
+		}
+		entries = append(entries, entryFile{path: filepath.Join(c.dir, d.Name()), size: info.Size(), modTime: info.ModTime()})
+	}
+	return entries, nil
+}
+
+// entryPath returns the path of the entry file of the key
+func (c *Cache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+entryFileExt)
+}
+
+// isExpired reads the header of the entry file and returns true if the entry has expired or is corrupted
+func isExpired(path string, now time.Time) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil {
+		return true
+	}
+	header := &entryHeader{}
+	if err := json.Unmarshal(line, header); err != nil {
+		return true
+	}
+	return header.Expires != nil && !now.Before(*header.Expires)
+}
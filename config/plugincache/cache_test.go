@@ -0,0 +1,252 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugincache
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+)
+
+const (
+	envHelperProcess = "PLUGINCACHE_TEST_HELPER_PROCESS"
+	envHelperID      = "PLUGINCACHE_TEST_HELPER_ID"
+
+	helperEntries   = 25
+	helperEntrySize = 256
+	helperMaxSize   = 40 * (helperEntrySize + 128)
+)
+
+func setupTestCacheHome(t *testing.T) string {
+	cacheHome := t.TempDir()
+	t.Setenv(config.EnvXDGCacheHome, cacheHome)
+	return cacheHome
+}
+
+func TestCachePutGet(t *testing.T) {
+	setupTestCacheHome(t)
+
+	c, err := New("test-plugin")
+	require.NoError(t, err)
+
+	data, found, err := c.Get("missing")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, data)
+
+	assert.NoError(t, c.Put("key", []byte("value\nwith newline")))
+	data, found, err = c.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value\nwith newline", string(data))
+
+	assert.NoError(t, c.Put("key", []byte("updated")))
+	data, found, err = c.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "updated", string(data))
+
+	assert.NoError(t, c.Delete("key"))
+	_, found, err = c.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	assert.NoError(t, c.Delete("key"))
+
+	assert.EqualError(t, c.Put("", []byte("value")), "key cannot be empty")
+}
+
+func TestCacheTTL(t *testing.T) {
+	setupTestCacheHome(t)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	c, err := New("test-plugin", WithTTL(time.Minute))
+	require.NoError(t, err)
+
+	assert.NoError(t, c.Put("default-ttl", []byte("value")))
+	assert.NoError(t, c.PutWithTTL("long-ttl", []byte("value"), time.Hour))
+	assert.NoError(t, c.PutWithTTL("no-ttl", []byte("value"), 0))
+
+	now = now.Add(30 * time.Second)
+	for _, key := range []string{"default-ttl", "long-ttl", "no-ttl"} {
+		_, found, err := c.Get(key)
+		assert.NoError(t, err)
+		assert.True(t, found, key)
+	}
+
+	now = now.Add(time.Minute)
+	_, found, err := c.Get("default-ttl")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	for _, key := range []string{"long-ttl", "no-ttl"} {
+		_, found, err := c.Get(key)
+		assert.NoError(t, err)
+		assert.True(t, found, key)
+	}
+
+	// Expired entries are evicted on the next put
+	assert.NoError(t, c.Put("other", []byte("value")))
+	_, err = os.Stat(c.entryPath("default-ttl"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCacheLRUEviction(t *testing.T) {
+	setupTestCacheHome(t)
+
+	entrySize := int64(len(`{"key":"key-0"}`) + 1 + 100)
+	c, err := New("test-plugin", WithMaxSize(3*entrySize))
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("x"), 100)
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		key := "key-" + strconv.Itoa(i)
+		require.NoError(t, c.Put(key, data))
+		modTime := base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, os.Chtimes(c.entryPath(key), modTime, modTime))
+	}
+
+	// Accessing key-0 makes key-1 the least recently used entry
+	_, found, err := c.Get("key-0")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	require.NoError(t, c.Put("key-3", data))
+	for key, expected := range map[string]bool{"key-0": true, "key-1": false, "key-2": true, "key-3": true} {
+		_, found, err := c.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, found, key)
+	}
+
+	err = c.Put("too-large", bytes.Repeat([]byte("x"), int(3*entrySize)))
+	assert.ErrorContains(t, err, "exceeds the max size")
+}
+
+func TestClear(t *testing.T) {
+	cacheHome := setupTestCacheHome(t)
+
+	c, err := New("test-plugin")
+	require.NoError(t, err)
+	other, err := New("other-plugin")
+	require.NoError(t, err)
+	require.NoError(t, c.Put("key", []byte("value")))
+	require.NoError(t, other.Put("key", []byte("value")))
+
+	require.NoError(t, c.Clear())
+	_, found, err := c.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, c.Put("key", []byte("value")))
+	require.NoError(t, Clear("test-plugin"))
+	_, found, err = c.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+	_, found, err = other.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+
+	require.NoError(t, ClearAll())
+	_, err = os.Stat(filepath.Join(cacheHome, "tanzu", "plugins"))
+	assert.True(t, os.IsNotExist(err))
+	_, found, err = other.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	// The cache can be used after being cleared
+	require.NoError(t, other.Put("key", []byte("value")))
+	_, found, err = other.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+}
+
+// TestCacheMultiProcess runs multiple processes putting and getting entries concurrently in
+// the same cache and verifies the cache is consistent and within its max size
+func TestCacheMultiProcess(t *testing.T) {
+	setupTestCacheHome(t)
+
+	const processes = 4
+	cmds := make([]*exec.Cmd, processes)
+	outputs := make([]*bytes.Buffer, processes)
+	for i := 0; i < processes; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestCacheHelperProcess$") //nolint:gosec
+		cmd.Env = append(os.Environ(), envHelperProcess+"=1", envHelperID+"="+strconv.Itoa(i))
+		outputs[i] = &bytes.Buffer{}
+		cmd.Stdout = outputs[i]
+		cmd.Stderr = outputs[i]
+		require.NoError(t, cmd.Start())
+		cmds[i] = cmd
+	}
+	for i, cmd := range cmds {
+		assert.NoError(t, cmd.Wait(), outputs[i].String())
+	}
+
+	c, err := New("test-plugin", WithMaxSize(helperMaxSize))
+	require.NoError(t, err)
+	entries, err := c.listEntries()
+	require.NoError(t, err)
+	assert.NotEmpty(t, entries)
+
+	var size int64
+	for _, e := range entries {
+		size += e.size
+	}
+	assert.LessOrEqual(t, size, int64(helperMaxSize))
+
+	for p := 0; p < processes; p++ {
+		for i := 0; i < helperEntries; i++ {
+			key := helperKey(p, i)
+			data, found, err := c.Get(key)
+			assert.NoError(t, err)
+			if found {
+				assert.Equal(t, helperData(key), data)
+			}
+		}
+	}
+}
+
+// TestCacheHelperProcess is not a real test, it is run as a subprocess by TestCacheMultiProcess
+func TestCacheHelperProcess(t *testing.T) {
+	if os.Getenv(envHelperProcess) != "1" {
+		t.Skip("helper process for TestCacheMultiProcess")
+	}
+	id, err := strconv.Atoi(os.Getenv(envHelperID))
+	require.NoError(t, err)
+
+	c, err := New("test-plugin", WithMaxSize(helperMaxSize))
+	require.NoError(t, err)
+	for i := 0; i < helperEntries; i++ {
+		key := helperKey(id, i)
+		require.NoError(t, c.Put(key, helperData(key)))
+
+		// Read the entries of the other processes while they are being written and evicted
+		for p := 0; p < 4; p++ {
+			other := helperKey(p, i)
+			data, found, err := c.Get(other)
+			require.NoError(t, err)
+			if found {
+				require.Equal(t, helperData(other), data)
+			}
+		}
+	}
+}
+
+func helperKey(process, i int) string {
+	return fmt.Sprintf("process-%d-key-%d", process, i)
+}
+
+func helperData(key string) []byte {
+	return bytes.Repeat([]byte(key+";"), helperEntrySize/(len(key)+1))
+}
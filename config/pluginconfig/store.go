@@ -0,0 +1,406 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pluginconfig provides a configuration store scoped to a plugin. The configuration of each
+// plugin is stored in <GetTanzuPluginConfigDir>/<plugin>/config.yaml and is updated with the same
+// file locking, atomic writes and comment preserving yaml nodes as the tanzu config.
+package pluginconfig
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/fslock"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/internal/atomicfile"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/nodeutils"
+)
+
+const (
+	// ConfigFileName is the name of the configuration file of the plugin
+	ConfigFileName = "config.yaml"
+	// KeySchemaVersion is the reserved top level key storing the schema version of the configuration
+	KeySchemaVersion = "schemaVersion"
+
+	lockFileName = ".config.lock"
+)
+
+// ErrKeyNotFound is returned when the key is not set in the configuration of the plugin
+var ErrKeyNotFound = errors.New("key not found")
+
+// lockTimeout is the time waiting on the file lock of the plugin configuration, overridden in tests
+var lockTimeout = config.DefaultLockTimeout
+
+// Migration migrates the configuration of the plugin to the schema Version. Migrate receives
+// the top level mapping node of the configuration and updates it in place.
+type Migration struct {
+	Version int
+	Migrate func(node *yaml.Node) error
+}
+
+// StoreOptions are the options of the plugin configuration store
+type StoreOptions struct {
+	Migrations []Migration
+}
+
+type StoreOption func(opts *StoreOptions)
+
+// WithMigrations registers the schema migrations of the plugin configuration. The migrations with a
+// version higher than the schema version of the configuration are applied in order of version when
+// the store is created, and the schema version is set to the highest version.
+func WithMigrations(migrations ...Migration) StoreOption {
+	return func(opts *StoreOptions) {
+		opts.Migrations = append(opts.Migrations, migrations...)
+	}
+}
+
+// Store is the configuration store of a plugin
+type Store struct {
+	plugin     string
+	dir        string
+	migrations []Migration
+}
+
+// New returns the configuration store of the plugin and applies the schema migrations, if any
+func New(plugin string, opts ...StoreOption) (*Store, error) {
+	options := &StoreOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	dir, err := pluginDir(plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := append([]Migration(nil), options.Migrations...)
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := range migrations {
+		if migrations[i].Version <= 0 {
+			return nil, errors.Errorf("invalid migration version %d, must be greater than 0", migrations[i].Version)
+		}
+		if migrations[i].Migrate == nil {
+			return nil, errors.Errorf("migration to version %d has no migrate function", migrations[i].Version)
+		}
+		if i > 0 && migrations[i].Version == migrations[i-1].Version {
+			return nil, errors.Errorf("duplicate migration version %d", migrations[i].Version)
+		}
+	}
+
+	s := &Store{plugin: plugin, dir: dir, migrations: migrations}
+	if len(migrations) != 0 {
+		if err := s.update(func(*yaml.Node) error { return nil }); err != nil {
+			return nil, errors.Wrapf(err, "failed to migrate the configuration of plugin %q", plugin)
+		}
+	}
+	return s, nil
+}
+
+// DeleteAll removes all the configuration and state of the plugin e.g. when the plugin is uninstalled
+func DeleteAll(plugin string) error {
+	dir, err := pluginDir(plugin)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return errors.Wrapf(err, "failed to delete the configuration of plugin %q", plugin)
+	}
+	return nil
+}
+
+// Path returns the path of the configuration file of the plugin
+func (s *Store) Path() string {
+	return filepath.Join(s.dir, ConfigFileName)
+}
+
+// SchemaVersion returns the schema version of the configuration of the plugin, 0 if not set
+func (s *Store) SchemaVersion() (int, error) {
+	node, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+	return getSchemaVersion(node.Content[0])
+}
+
+// Get decodes the value of the key into value. The key is a dot separated path e.g. "registry.host".
+// ErrKeyNotFound is returned if the key is not set.
+func (s *Store) Get(key string, value interface{}) error {
+	keys, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	node, err := s.load()
+	if err != nil {
+		return err
+	}
+	valueNode := findValueNode(node.Content[0], keys)
+	if valueNode == nil {
+		return errors.Wrapf(ErrKeyNotFound, "%q", key)
+	}
+	if err := valueNode.Decode(value); err != nil {
+		return errors.Wrapf(err, "failed to decode the value of %q", key)
+	}
+	return nil
+}
+
+// GetString returns the string value of the key
+func (s *Store) GetString(key string) (string, error) {
+	var value string
+	err := s.Get(key, &value)
+	return value, err
+}
+
+// GetBool returns the bool value of the key
+func (s *Store) GetBool(key string) (bool, error) {
+	var value bool
+	err := s.Get(key, &value)
+	return value, err
+}
+
+// GetInt returns the int value of the key
+func (s *Store) GetInt(key string) (int, error) {
+	var value int
+	err := s.Get(key, &value)
+	return value, err
+}
+
+// GetDuration returns the duration value of the key e.g. 30s, 5m
+func (s *Store) GetDuration(key string) (time.Duration, error) {
+	value, err := s.GetString(key)
+	if err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to decode the value of %q", key)
+	}
+	return d, nil
+}
+
+// Set encodes the value and sets it as the value of the key. Missing parent keys are created and
+// the comments of the existing nodes are preserved.
+func (s *Store) Set(key string, value interface{}) error {
+	keys, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	if keys[0] == KeySchemaVersion {
+		return errors.Errorf("key %q is reserved", KeySchemaVersion)
+	}
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		return errors.Wrapf(err, "failed to encode the value of %q", key)
+	}
+
+	return s.update(func(node *yaml.Node) error {
+		parent := node
+		for i, k := range keys[:len(keys)-1] {
+			child := findValueNode(parent, []string{k})
+			if child == nil {
+				parent.Content = append(parent.Content, nodeutils.CreateMappingNode(k)...)
+				child = parent.Content[len(parent.Content)-1]
+			}
+			if child.Kind != yaml.MappingNode {
+				return errors.Errorf("cannot set %q, the value of %q is not a map", key, strings.Join(keys[:i+1], "."))
+			}
+			parent = child
+		}
+
+		last := keys[len(keys)-1]
+		idx := nodeutils.GetNodeIndex(parent.Content, last)
+		if idx == -1 {
+			parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: nodeutils.NodeTagStr, Value: last}, valueNode)
+			return nil
+		}
+		preserveComments(parent.Content[idx], valueNode)
+		parent.Content[idx] = valueNode
+		return nil
+	})
+}
+
+// Delete removes the key from the configuration of the plugin. Deleting a key which is not set is a no-op.
+func (s *Store) Delete(key string) error {
+	keys, err := splitKey(key)
+	if err != nil {
+		return err
+	}
+	if keys[0] == KeySchemaVersion {
+		return errors.Errorf("key %q is reserved", KeySchemaVersion)
+	}
+
+	return s.update(func(node *yaml.Node) error {
+		parent := findValueNode(node, keys[:len(keys)-1])
+		if parent == nil || parent.Kind != yaml.MappingNode {
+			return nil
+		}
+		idx := nodeutils.GetNodeIndex(parent.Content, keys[len(keys)-1])
+		if idx == -1 {
+			return nil
+		}
+		parent.Content = append(parent.Content[:idx-1], parent.Content[idx+1:]...)
+		return nil
+	})
+}
+
+// update acquires the file lock of the plugin configuration, applies the pending migrations and
+// the mutation to the top level mapping node and persists the configuration
+func (s *Store) update(mutate func(node *yaml.Node) error) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return errors.Wrapf(err, "could not make the configuration directory of plugin %q", s.plugin)
+	}
+	lock := fslock.New(filepath.Join(s.dir, lockFileName))
+	if err := lock.LockWithTimeout(lockTimeout); err != nil {
+		return errors.Wrapf(err, "cannot acquire lock for the configuration of plugin %q", s.plugin)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	node, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := s.migrate(node.Content[0]); err != nil {
+		return err
+	}
+	if err := mutate(node.Content[0]); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the configuration")
+	}
+	if err := atomicfile.WriteFile(s.Path(), data, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write the configuration of plugin %q", s.plugin)
+	}
+	return nil
+}
+
+// migrate applies the migrations with a version higher than the schema version of the configuration
+func (s *Store) migrate(node *yaml.Node) error {
+	if len(s.migrations) == 0 {
+		return nil
+	}
+	version, err := getSchemaVersion(node)
+	if err != nil {
+		return err
+	}
+	for _, m := range s.migrations {
+		if m.Version <= version {
+			continue
+		}
+		if err := m.Migrate(node); err != nil {
+			return errors.Wrapf(err, "failed to migrate to schema version %d", m.Version)
+		}
+		if node.Kind != yaml.MappingNode {
+			return errors.Errorf("migration to schema version %d did not return a map", m.Version)
+		}
+		setSchemaVersion(node, m.Version)
+		version = m.Version
+	}
+	return nil
+}
+
+// load reads the configuration of the plugin. An empty document is returned if the file doesn't exist.
+func (s *Store) load() (*yaml.Node, error) {
+	node := &yaml.Node{}
+	data, err := os.ReadFile(s.Path())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read the configuration of plugin %q", s.plugin)
+	}
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the configuration of plugin %q", s.plugin)
+	}
+	if node.Kind == 0 {
+		node.Kind = yaml.DocumentNode
+		node.Content = []*yaml.Node{{Kind: yaml.MappingNode}}
+	}
+	if node.Kind != yaml.DocumentNode || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.Errorf("the configuration of plugin %q is not a map", s.plugin)
+	}
+	return node, nil
+}
+
+// pluginDir returns the configuration directory of the plugin
+func pluginDir(plugin string) (string, error) {
+	if plugin == "" || plugin == "." || plugin == ".." || strings.ContainsAny(plugin, `/\`) {
+		return "", errors.Errorf("invalid plugin name %q", plugin)
+	}
+	pluginsDir, err := config.GetTanzuPluginConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pluginsDir, plugin), nil
+}
+
+// splitKey splits the dot separated key into its path elements
+func splitKey(key string) ([]string, error) {
+	keys := strings.Split(key, ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, errors.Errorf("invalid key %q", key)
+		}
+	}
+	return keys, nil
+}
+
+// findValueNode returns the value node at the path of keys in the mapping node or nil if not found
+func findValueNode(node *yaml.Node, keys []string) *yaml.Node {
+	for _, k := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		idx := nodeutils.GetNodeIndex(node.Content, k)
+		if idx == -1 {
+			return nil
+		}
+		node = node.Content[idx]
+	}
+	return node
+}
+
+// preserveComments copies the comments of the replaced value node which are not set on the new value node
+func preserveComments(oldNode, newNode *yaml.Node) {
+	if newNode.HeadComment == "" {
+		newNode.HeadComment = oldNode.HeadComment
+	}
+	if newNode.LineComment == "" {
+		newNode.LineComment = oldNode.LineComment
+	}
+	if newNode.FootComment == "" {
+		newNode.FootComment = oldNode.FootComment
+	}
+}
+
+func getSchemaVersion(node *yaml.Node) (int, error) {
+	valueNode := findValueNode(node, []string{KeySchemaVersion})
+	if valueNode == nil {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(valueNode.Value)
+	if err != nil {
+		return 0, errors.Errorf("invalid schema version %q", valueNode.Value)
+	}
+	return version, nil
+}
+
+func setSchemaVersion(node *yaml.Node, version int) {
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	idx := nodeutils.GetNodeIndex(node.Content, KeySchemaVersion)
+	if idx == -1 {
+		node.Content = append([]*yaml.Node{{Kind: yaml.ScalarNode, Tag: nodeutils.NodeTagStr, Value: KeySchemaVersion}, valueNode}, node.Content...)
+		return
+	}
+	preserveComments(node.Content[idx], valueNode)
+	node.Content[idx] = valueNode
+}
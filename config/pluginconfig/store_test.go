@@ -0,0 +1,228 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package pluginconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func setupTestHome(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+func TestStoreGetSetDelete(t *testing.T) {
+	setupTestHome(t)
+
+	s, err := New("test-plugin")
+	assert.NoError(t, err)
+
+	type registry struct {
+		Host     string `yaml:"host"`
+		Insecure bool   `yaml:"insecure"`
+	}
+
+	assert.NoError(t, s.Set("registry", registry{Host: "example.com"}))
+	assert.NoError(t, s.Set("registry.insecure", true))
+	assert.NoError(t, s.Set("timeouts.sync", "30s"))
+	assert.NoError(t, s.Set("retries", 3))
+
+	var r registry
+	assert.NoError(t, s.Get("registry", &r))
+	assert.Equal(t, registry{Host: "example.com", Insecure: true}, r)
+
+	host, err := s.GetString("registry.host")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com", host)
+
+	insecure, err := s.GetBool("registry.insecure")
+	assert.NoError(t, err)
+	assert.True(t, insecure)
+
+	retries, err := s.GetInt("retries")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, retries)
+
+	timeout, err := s.GetDuration("timeouts.sync")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	assert.NoError(t, s.Delete("registry.insecure"))
+	_, err = s.GetBool("registry.insecure")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+
+	// Deleting a key which is not set is a no-op
+	assert.NoError(t, s.Delete("registry.missing"))
+	assert.NoError(t, s.Delete("missing.key"))
+
+	err = s.Set("retries.max", 5)
+	assert.EqualError(t, err, `cannot set "retries.max", the value of "retries" is not a map`)
+
+	err = s.Set("registry..host", "x")
+	assert.EqualError(t, err, `invalid key "registry..host"`)
+
+	err = s.Set(KeySchemaVersion, 2)
+	assert.EqualError(t, err, `key "schemaVersion" is reserved`)
+
+	info, err := os.Stat(s.Path())
+	assert.NoError(t, err)
+	if info.Mode().Perm() != 0o600 && os.PathSeparator == '/' {
+		t.Errorf("expected the config file permissions to be 0600, got %v", info.Mode().Perm())
+	}
+}
+
+func TestStorePreservesComments(t *testing.T) {
+	setupTestHome(t)
+
+	s, err := New("test-plugin")
+	assert.NoError(t, err)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(s.Path()), 0o700))
+
+	content := `# plugin settings
+registry:
+    # the registry host
+    host: example.com # default host
+mode: fast
+`
+	assert.NoError(t, os.WriteFile(s.Path(), []byte(content), 0o600))
+
+	assert.NoError(t, s.Set("registry.host", "other.example.com"))
+	assert.NoError(t, s.Set("mode", "slow"))
+
+	data, err := os.ReadFile(s.Path())
+	assert.NoError(t, err)
+	expected := `# plugin settings
+registry:
+    # the registry host
+    host: other.example.com # default host
+mode: slow
+`
+	assert.Equal(t, expected, string(data))
+}
+
+func TestStoreMigrations(t *testing.T) {
+	setupTestHome(t)
+
+	s, err := New("test-plugin")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set("endpoint", "https://example.com"))
+
+	version, err := s.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	var applied []int
+	migrations := []Migration{
+		{
+			Version: 2,
+			Migrate: func(node *yaml.Node) error {
+				applied = append(applied, 2)
+				return nil
+			},
+		},
+		{
+			Version: 1,
+			Migrate: func(node *yaml.Node) error {
+				applied = append(applied, 1)
+				// Rename endpoint to server.url
+				for i := 0; i < len(node.Content); i += 2 {
+					if node.Content[i].Value == "endpoint" {
+						url := node.Content[i+1]
+						node.Content = append(node.Content[:i], node.Content[i+2:]...)
+						server := &yaml.Node{Kind: yaml.MappingNode}
+						server.Content = []*yaml.Node{{Kind: yaml.ScalarNode, Value: "url"}, url}
+						node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "server"}, server)
+						break
+					}
+				}
+				return nil
+			},
+		},
+	}
+
+	s, err = New("test-plugin", WithMigrations(migrations...))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, applied)
+
+	version, err = s.SchemaVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	url, err := s.GetString("server.url")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com", url)
+
+	// The migrations are not applied again
+	applied = nil
+	_, err = New("test-plugin", WithMigrations(migrations...))
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	_, err = New("test-plugin", WithMigrations(Migration{Version: 3, Migrate: func(*yaml.Node) error {
+		return errors.New("boom")
+	}}))
+	assert.ErrorContains(t, err, "failed to migrate to schema version 3: boom")
+
+	_, err = New("test-plugin", WithMigrations(migrations[0], migrations[0]))
+	assert.EqualError(t, err, "duplicate migration version 2")
+}
+
+func TestStoreConcurrentUpdates(t *testing.T) {
+	setupTestHome(t)
+
+	s, err := New("test-plugin")
+	assert.NoError(t, err)
+
+	const count = 20
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, s.Set("keys.key"+string(rune('a'+i)), i))
+		}(i)
+	}
+	wg.Wait()
+
+	var keys map[string]int
+	assert.NoError(t, s.Get("keys", &keys))
+	assert.Len(t, keys, count)
+}
+
+func TestDeleteAll(t *testing.T) {
+	setupTestHome(t)
+
+	s, err := New("test-plugin")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set("key", "value"))
+
+	other, err := New("other-plugin")
+	assert.NoError(t, err)
+	assert.NoError(t, other.Set("key", "value"))
+
+	assert.NoError(t, DeleteAll("test-plugin"))
+	_, err = os.Stat(filepath.Dir(s.Path()))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = s.GetString("key")
+	assert.True(t, errors.Is(err, ErrKeyNotFound))
+	value, err := other.GetString("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	for _, name := range []string{"", ".", "..", "../other-plugin", `a\b`} {
+		assert.EqualError(t, DeleteAll(name), fmt.Sprintf("invalid plugin name %q", name))
+	}
+}
@@ -396,7 +396,7 @@ func setServer(node *yaml.Node, s *configtypes.Server) (persist bool, err error)
 			if err != nil {
 				return false, err
 			}
-			persist, err = nodeutils.MergeNodes(newServerNode.Content[0], serverNode)
+			persist, err = nodeutils.MergeNodes(newServerNode.Content[0], serverNode, nodeutils.WithPatchStrategyKey(KeyServers), nodeutils.WithPatchStrategies(patchStrategies))
 			if err != nil {
 				return false, err
 			}
@@ -406,7 +406,7 @@ func setServer(node *yaml.Node, s *configtypes.Server) (persist bool, err error)
 				return false, err
 			}
 			if persistDiscoverySources {
-				_, err = nodeutils.MergeNodes(newServerNode.Content[0], serverNode)
+				_, err = nodeutils.MergeNodes(newServerNode.Content[0], serverNode, nodeutils.WithPatchStrategyKey(KeyServers), nodeutils.WithPatchStrategies(patchStrategies))
 				if err != nil {
 					return false, err
 				}
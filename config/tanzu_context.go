@@ -177,7 +177,12 @@ func ForCustomPath(customPath string) ResourceOptions {
 // -> projectName        = ""
 // -> spaceName          = ""
 // -> clusterGroupName   = ""
+//
+// An empty `contextName` refers to the context overriding the active context set with SetContextOverride.
 func GetKubeconfigForContext(contextName string, opts ...ResourceOptions) ([]byte, error) {
+	if contextName == "" {
+		contextName = GetContextOverride()
+	}
 	ctx, err := GetContext(contextName)
 	if err != nil {
 		return nil, err
@@ -260,6 +260,37 @@ func (c *Context) IsManagementCluster() bool {
 	return c != nil && c.Target == TargetK8s && c.ClusterOpts != nil && c.ClusterOpts.IsManagementCluster
 }
 
+// GetPriority returns the priority of the discovery source. Returns 0 for discovery
+// types that do not support priority.
+func (d *PluginDiscovery) GetPriority() int {
+	switch {
+	case d.OCI != nil:
+		return d.OCI.Priority
+	case d.Local != nil:
+		return d.Local.Priority
+	case d.Kubernetes != nil:
+		return d.Kubernetes.Priority
+	case d.REST != nil:
+		return d.REST.Priority
+	}
+	return 0
+}
+
+// IsDisabled tells if the discovery source is disabled.
+func (d *PluginDiscovery) IsDisabled() bool {
+	switch {
+	case d.OCI != nil:
+		return d.OCI.Disabled
+	case d.Local != nil:
+		return d.Local.Disabled
+	case d.Kubernetes != nil:
+		return d.Kubernetes.Disabled
+	case d.REST != nil:
+		return d.REST.Disabled
+	}
+	return false
+}
+
 // SetUnstableVersionSelector will help determine the unstable versions supported
 // In order of restrictiveness:
 // "all" -> "alpha" -> "experimental" -> "none"
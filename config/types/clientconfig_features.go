@@ -26,20 +26,25 @@ func (c *ClientConfig) IsFeatureFlagSet(plugin, feature string) bool {
 }
 
 // IsConfigFeatureActivated return true if the feature is activated, false if not. An error if the featurePath is malformed
+// A feature flag override (see GetFeatureOverride) takes precedence over the value in the configuration object
 func (c *ClientConfig) IsConfigFeatureActivated(featurePath string) (bool, error) {
 	plugin, flag, err := c.SplitFeaturePath(featurePath)
 	if err != nil {
 		return false, err
 	}
 
-	if c.ClientOptions == nil || c.ClientOptions.Features == nil ||
-		c.ClientOptions.Features[plugin] == nil || c.ClientOptions.Features[plugin][flag] == "" {
-		return false, nil
+	value, overridden := GetFeatureOverride(plugin, flag)
+	if !overridden {
+		if c.ClientOptions == nil || c.ClientOptions.Features == nil ||
+			c.ClientOptions.Features[plugin] == nil || c.ClientOptions.Features[plugin][flag] == "" {
+			return false, nil
+		}
+		value = c.ClientOptions.Features[plugin][flag]
 	}
 
-	booleanValue, err := strconv.ParseBool(c.ClientOptions.Features[plugin][flag])
+	booleanValue, err := strconv.ParseBool(value)
 	if err != nil {
-		errMsg := "error converting " + featurePath + " entry '" + c.ClientOptions.Features[plugin][flag] + "' to boolean value: " + err.Error()
+		errMsg := "error converting " + featurePath + " entry '" + value + "' to boolean value: " + err.Error()
 		return false, errors.New(errMsg)
 	}
 	return booleanValue, nil
@@ -24,3 +24,27 @@ func (c ContextSorter) Less(i, j int) bool {
 	}
 	return c[i].Name < c[j].Name
 }
+
+// DiscoverySorter is a type that implements the sort interface for PluginDiscovery to sort
+// by effective priority. Enabled sources come before disabled sources and sources with
+// higher priority come first. Use with sort.Stable to retain the configured order of
+// sources with the same priority.
+type DiscoverySorter []PluginDiscovery
+
+// Len returns the length of the DiscoverySorter.
+func (d DiscoverySorter) Len() int {
+	return len(d)
+}
+
+// Swap swaps the elements at the given indices.
+func (d DiscoverySorter) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+// Less compares the PluginDiscovery (by disabled state and then by priority) at the given indices.
+func (d DiscoverySorter) Less(i, j int) bool {
+	if d[i].IsDisabled() != d[j].IsDisabled() {
+		return !d[i].IsDisabled()
+	}
+	return d[i].GetPriority() > d[j].GetPriority()
+}
@@ -33,3 +33,38 @@ func TestContextSorter_Sort(t *testing.T) {
 		}
 	}
 }
+
+func TestDiscoverySorter_Sort(t *testing.T) {
+	// Create a list of PluginDiscovery instances to be sorted
+	discoveries := []PluginDiscovery{
+		{OCI: &OCIDiscovery{Name: "oci-default"}},
+		{Local: &LocalDiscovery{Name: "local-disabled", Priority: 100, Disabled: true}},
+		{REST: &GenericRESTDiscovery{Name: "rest-high", Priority: 10}},
+		{Kubernetes: &KubernetesDiscovery{Name: "k8s-default"}},
+		{OCI: &OCIDiscovery{Name: "oci-low", Priority: -1}},
+	}
+
+	// Sort the discoveries using the DiscoverySorter
+	sort.Stable(DiscoverySorter(discoveries))
+
+	// Verify the sorted order, which should be by disabled state and then by priority
+	// retaining the order of sources with the same priority
+	expectedOrder := []string{"rest-high", "oci-default", "k8s-default", "oci-low", "local-disabled"}
+
+	for i, discovery := range discoveries {
+		var name string
+		switch {
+		case discovery.OCI != nil:
+			name = discovery.OCI.Name
+		case discovery.Local != nil:
+			name = discovery.Local.Name
+		case discovery.REST != nil:
+			name = discovery.REST.Name
+		case discovery.Kubernetes != nil:
+			name = discovery.Kubernetes.Name
+		}
+		if name != expectedOrder[i] {
+			t.Errorf("Expected %v, but got %v", expectedOrder[i], name)
+		}
+	}
+}
@@ -235,6 +235,39 @@ type OCIDiscovery struct {
 	// Contains a directory containing YAML files, each of which contains single
 	// CLIPlugin API resource.
 	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	// Digest pins the image to the specified content digest. When set, the image is
+	// resolved by digest instead of the (mutable) tag.
+	// E.g., sha256:4b2a0f1a0c6f4b6e6e0d3c0e8f3e0e1c4b5a6d7e8f9a0b1c2d3e4f5a6b7c8d9e
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+	// Verification specifies how the signature of the image should be verified
+	Verification *OCIImageVerification `json:"verification,omitempty" yaml:"verification,omitempty"`
+	// Priority of the discovery source. Sources with higher priority take precedence
+	// when multiple sources provide the same plugin. Defaults to 0.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Disabled is set to true if the discovery source is temporarily disabled
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+// OCIImageVerification provides the configuration to verify the signature of an OCI image.
+// Only one of PublicKey or Keyless must be set.
+type OCIImageVerification struct {
+	// PublicKey is the cosign public key used to verify the signature of the image.
+	// It can either be the PEM encoded key or a key reference supported by cosign.
+	// E.g., k8s://tanzu-system/cosign-key, /path/to/cosign.pub
+	PublicKey string `json:"publicKey,omitempty" yaml:"publicKey,omitempty"`
+	// Keyless is set if the signature of the image is verified using keyless signing
+	Keyless *OCIKeylessVerification `json:"keyless,omitempty" yaml:"keyless,omitempty"`
+}
+
+// OCIKeylessVerification provides the identity expected to have signed an OCI image using
+// keyless signing
+type OCIKeylessVerification struct {
+	// Issuer is the OIDC issuer of the signing identity.
+	// E.g., https://token.actions.githubusercontent.com
+	Issuer string `json:"issuer,omitempty" yaml:"issuer,omitempty"`
+	// Identity is the identity(subject) of the signer in the certificate.
+	// E.g., https://github.com/vmware-tanzu/tanzu-cli/.github/workflows/release.yaml@refs/heads/main
+	Identity string `json:"identity,omitempty" yaml:"identity,omitempty"`
 }
 
 // GenericRESTDiscovery provides a plugin discovery mechanism via any REST API
@@ -250,6 +283,11 @@ type GenericRESTDiscovery struct {
 	// BasePath is the base URL path of the plugin discovery API.
 	// E.g., /v1alpha1/cli/plugins
 	BasePath string `json:"basePath,omitempty" yaml:"basePath,omitempty"`
+	// Priority of the discovery source. Sources with higher priority take precedence
+	// when multiple sources provide the same plugin. Defaults to 0.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Disabled is set to true if the discovery source is temporarily disabled
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
 }
 
 // KubernetesDiscovery provides a plugin discovery mechanism via the Kubernetes API server.
@@ -266,6 +304,11 @@ type KubernetesDiscovery struct {
 	// Version of the CLIPlugins API to query.
 	// E.g., v1alpha1
 	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Priority of the discovery source. Sources with higher priority take precedence
+	// when multiple sources provide the same plugin. Defaults to 0.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Disabled is set to true if the discovery source is temporarily disabled
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
 }
 
 // LocalDiscovery is a artifact discovery endpoint utilizing a local host OS.
@@ -276,6 +319,11 @@ type LocalDiscovery struct {
 	// containing YAML files, each of which contains single
 	// CLIPlugin API resource.
 	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Priority of the discovery source. Sources with higher priority take precedence
+	// when multiple sources provide the same plugin. Defaults to 0.
+	Priority int `json:"priority,omitempty" yaml:"priority,omitempty"`
+	// Disabled is set to true if the discovery source is temporarily disabled
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
 }
 
 // PluginRepository is a CLI plugin repository
@@ -0,0 +1,142 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FeatureFlagType is the type of the value of a feature flag
+type FeatureFlagType string
+
+const (
+	// FeatureFlagTypeBool is a feature flag with a boolean value
+	FeatureFlagTypeBool FeatureFlagType = "bool"
+	// FeatureFlagTypeInt is a feature flag with an integer value
+	FeatureFlagTypeInt FeatureFlagType = "int"
+	// FeatureFlagTypeDuration is a feature flag with a duration value e.g. 30s, 5m
+	FeatureFlagTypeDuration FeatureFlagType = "duration"
+	// FeatureFlagTypeString is a feature flag with a string value. The value can be restricted
+	// to a set of allowed values to declare an enum
+	FeatureFlagTypeString FeatureFlagType = "string"
+)
+
+// FeatureFlagExpiryLayout is the layout of the expiry date of a feature flag
+const FeatureFlagExpiryLayout = "2006-01-02"
+
+// FeatureFlagSpec declares a feature flag of a plugin
+type FeatureFlagSpec struct {
+	// Name of the feature flag
+	Name string `json:"name" yaml:"name"`
+	// Type of the value of the feature flag. Defaults to bool
+	Type FeatureFlagType `json:"type,omitempty" yaml:"type,omitempty"`
+	// Default value of the feature flag used when the flag is not set in the config.
+	// Defaults to the zero value of the type
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// AllowedValues restricts the values of a string feature flag
+	AllowedValues []string `json:"allowedValues,omitempty" yaml:"allowedValues,omitempty"`
+	// Description of the feature flag
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Owner of the feature flag e.g. the team or the individual responsible for removing it
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	// Expiry is the date (YYYY-MM-DD) after which the feature flag should be removed
+	Expiry string `json:"expiry,omitempty" yaml:"expiry,omitempty"`
+}
+
+// GetType returns the type of the feature flag
+func (s *FeatureFlagSpec) GetType() FeatureFlagType {
+	if s.Type == "" {
+		return FeatureFlagTypeBool
+	}
+	return s.Type
+}
+
+// GetDefault returns the default value of the feature flag
+func (s *FeatureFlagSpec) GetDefault() string {
+	if s.Default != "" {
+		return s.Default
+	}
+	switch s.GetType() {
+	case FeatureFlagTypeBool:
+		return "false"
+	case FeatureFlagTypeInt:
+		return "0"
+	case FeatureFlagTypeDuration:
+		return "0s"
+	}
+	return ""
+}
+
+// Validate returns an error if the feature flag declaration is not valid
+func (s *FeatureFlagSpec) Validate() error {
+	if s.Name == "" {
+		return errors.New("feature flag name cannot be empty")
+	}
+	switch s.GetType() {
+	case FeatureFlagTypeBool, FeatureFlagTypeInt, FeatureFlagTypeDuration, FeatureFlagTypeString:
+	default:
+		return errors.Errorf("feature flag %q: type %q is not valid", s.Name, s.Type)
+	}
+	if len(s.AllowedValues) != 0 && s.GetType() != FeatureFlagTypeString {
+		return errors.Errorf("feature flag %q: allowed values can only be specified for type %q", s.Name, FeatureFlagTypeString)
+	}
+	if s.Default != "" || len(s.AllowedValues) != 0 {
+		if err := s.ValidateValue(s.GetDefault()); err != nil {
+			return errors.Wrap(err, "invalid default value")
+		}
+	}
+	if s.Expiry != "" {
+		if _, err := time.Parse(FeatureFlagExpiryLayout, s.Expiry); err != nil {
+			return errors.Errorf("feature flag %q: expiry %q is not a valid date of the form YYYY-MM-DD", s.Name, s.Expiry)
+		}
+	}
+	return nil
+}
+
+// ValidateValue returns an error if the value is not valid for the feature flag
+func (s *FeatureFlagSpec) ValidateValue(value string) error {
+	var err error
+	switch s.GetType() {
+	case FeatureFlagTypeBool:
+		_, err = strconv.ParseBool(value)
+	case FeatureFlagTypeInt:
+		_, err = strconv.Atoi(value)
+	case FeatureFlagTypeDuration:
+		_, err = time.ParseDuration(value)
+	case FeatureFlagTypeString:
+		if len(s.AllowedValues) != 0 && !containsString(s.AllowedValues, value) {
+			return errors.Errorf("feature flag %q: value %q is not one of the allowed values [%s]", s.Name, value, strings.Join(s.AllowedValues, ", "))
+		}
+	}
+	if err != nil {
+		return errors.Errorf("feature flag %q: value %q is not a valid %s", s.Name, value, s.GetType())
+	}
+	return nil
+}
+
+// IsExpired returns true if the expiry date of the feature flag is before the specified time
+func (s *FeatureFlagSpec) IsExpired(now time.Time) bool {
+	if s.Expiry == "" {
+		return false
+	}
+	expiry, err := time.Parse(FeatureFlagExpiryLayout, s.Expiry)
+	if err != nil {
+		return false
+	}
+	// The feature flag is valid through the end of the expiry date
+	return now.UTC().After(expiry.AddDate(0, 0, 1))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
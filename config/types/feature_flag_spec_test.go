@@ -0,0 +1,81 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureFlagSpecValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		spec   FeatureFlagSpec
+		errStr string
+	}{
+		{
+			name: "bool flag with defaults",
+			spec: FeatureFlagSpec{Name: "flag"},
+		},
+		{
+			name: "duration flag with default and expiry",
+			spec: FeatureFlagSpec{Name: "flag", Type: FeatureFlagTypeDuration, Default: "30s", Expiry: "2024-12-31"},
+		},
+		{
+			name: "enum flag",
+			spec: FeatureFlagSpec{Name: "flag", Type: FeatureFlagTypeString, Default: "json", AllowedValues: []string{"json", "yaml"}},
+		},
+		{
+			name:   "empty name",
+			spec:   FeatureFlagSpec{},
+			errStr: "feature flag name cannot be empty",
+		},
+		{
+			name:   "invalid type",
+			spec:   FeatureFlagSpec{Name: "flag", Type: "float"},
+			errStr: "feature flag \"flag\": type \"float\" is not valid",
+		},
+		{
+			name:   "allowed values for non string flag",
+			spec:   FeatureFlagSpec{Name: "flag", Type: FeatureFlagTypeInt, AllowedValues: []string{"1"}},
+			errStr: "allowed values can only be specified for type \"string\"",
+		},
+		{
+			name:   "default not in allowed values",
+			spec:   FeatureFlagSpec{Name: "flag", Type: FeatureFlagTypeString, Default: "xml", AllowedValues: []string{"json", "yaml"}},
+			errStr: "invalid default value: feature flag \"flag\": value \"xml\" is not one of the allowed values [json, yaml]",
+		},
+		{
+			name:   "invalid default duration",
+			spec:   FeatureFlagSpec{Name: "flag", Type: FeatureFlagTypeDuration, Default: "30"},
+			errStr: "value \"30\" is not a valid duration",
+		},
+		{
+			name:   "invalid expiry",
+			spec:   FeatureFlagSpec{Name: "flag", Expiry: "31-12-2024"},
+			errStr: "expiry \"31-12-2024\" is not a valid date of the form YYYY-MM-DD",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.Validate()
+			if tc.errStr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.errStr)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagSpecIsExpired(t *testing.T) {
+	spec := FeatureFlagSpec{Name: "flag", Expiry: "2024-06-30"}
+
+	assert.False(t, spec.IsExpired(time.Date(2024, 6, 30, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, spec.IsExpired(time.Date(2024, 7, 1, 0, 1, 0, 0, time.UTC)))
+	assert.False(t, (&FeatureFlagSpec{Name: "flag"}).IsExpired(time.Now()))
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvFeatureOverridePrefix is the prefix of the environment variables overriding feature flags.
+// The feature flag features.<plugin>.<key> is overridden by TANZU_FEATURE_<PLUGIN>_<KEY> where
+// the plugin and key are uppercased and any character other than letters and digits is replaced by '_'
+// E.g., features.global.context-aware-cli is overridden by TANZU_FEATURE_GLOBAL_CONTEXT_AWARE_CLI
+const EnvFeatureOverridePrefix = "TANZU_FEATURE_"
+
+// featureOverrides holds the feature flags overridden for the current process keyed by plugin and key
+var featureOverrides = struct {
+	sync.RWMutex
+	values map[string]map[string]string
+}{values: make(map[string]map[string]string)}
+
+// SetFeatureOverride overrides the value of the feature flag for the current process.
+// The override is not persisted to the config and takes precedence over the
+// environment variable override and the value in the config.
+func SetFeatureOverride(plugin, key, value string) {
+	featureOverrides.Lock()
+	defer featureOverrides.Unlock()
+	if featureOverrides.values[plugin] == nil {
+		featureOverrides.values[plugin] = make(map[string]string)
+	}
+	featureOverrides.values[plugin][key] = value
+}
+
+// ResetFeatureOverrides removes all the feature flags overridden with SetFeatureOverride
+func ResetFeatureOverrides() {
+	featureOverrides.Lock()
+	defer featureOverrides.Unlock()
+	featureOverrides.values = make(map[string]map[string]string)
+}
+
+// GetFeatureOverride returns the overridden value of the feature flag and true if the feature flag is
+// overridden either for the current process (see SetFeatureOverride) or with the environment variable
+// (see FeatureOverrideEnvVar).
+func GetFeatureOverride(plugin, key string) (string, bool) {
+	featureOverrides.RLock()
+	val, ok := featureOverrides.values[plugin][key]
+	featureOverrides.RUnlock()
+	if ok {
+		return val, true
+	}
+	if val, ok := os.LookupEnv(FeatureOverrideEnvVar(plugin, key)); ok && val != "" {
+		return val, true
+	}
+	return "", false
+}
+
+// FeatureOverrideEnvVar returns the name of the environment variable overriding the feature flag
+func FeatureOverrideEnvVar(plugin, key string) string {
+	return EnvFeatureOverridePrefix + toEnvVarName(plugin) + "_" + toEnvVarName(key)
+}
+
+func toEnvVarName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(s))
+}
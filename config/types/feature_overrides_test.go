@@ -0,0 +1,66 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureOverrideEnvVar(t *testing.T) {
+	assert.Equal(t, "TANZU_FEATURE_GLOBAL_CONTEXT_AWARE_CLI", FeatureOverrideEnvVar("global", "context-aware-cli"))
+	assert.Equal(t, "TANZU_FEATURE_MANAGEMENT_CLUSTER_DUAL_STACK_IPV4_PRIMARY", FeatureOverrideEnvVar("management-cluster", "dual-stack-ipv4-primary"))
+}
+
+func TestGetFeatureOverride(t *testing.T) {
+	defer ResetFeatureOverrides()
+
+	_, ok := GetFeatureOverride("plugin1", "feature1")
+	assert.False(t, ok)
+
+	t.Setenv("TANZU_FEATURE_PLUGIN1_FEATURE1", "true")
+	val, ok := GetFeatureOverride("plugin1", "feature1")
+	assert.True(t, ok)
+	assert.Equal(t, "true", val)
+
+	// The override for the current process takes precedence over the environment variable
+	SetFeatureOverride("plugin1", "feature1", "false")
+	val, ok = GetFeatureOverride("plugin1", "feature1")
+	assert.True(t, ok)
+	assert.Equal(t, "false", val)
+
+	ResetFeatureOverrides()
+	val, ok = GetFeatureOverride("plugin1", "feature1")
+	assert.True(t, ok)
+	assert.Equal(t, "true", val)
+}
+
+func TestIsConfigFeatureActivatedWithOverride(t *testing.T) {
+	defer ResetFeatureOverrides()
+
+	cfg := &ClientConfig{
+		ClientOptions: &ClientOptions{
+			Features: map[string]FeatureMap{"plugin1": {"feature1": "true"}},
+		},
+	}
+
+	activated, err := cfg.IsConfigFeatureActivated("features.plugin1.feature1")
+	assert.NoError(t, err)
+	assert.True(t, activated)
+
+	t.Setenv("TANZU_FEATURE_PLUGIN1_FEATURE1", "false")
+	activated, err = cfg.IsConfigFeatureActivated("features.plugin1.feature1")
+	assert.NoError(t, err)
+	assert.False(t, activated)
+
+	SetFeatureOverride("plugin1", "feature2", "true")
+	activated, err = cfg.IsConfigFeatureActivated("features.plugin1.feature2")
+	assert.NoError(t, err)
+	assert.True(t, activated)
+
+	SetFeatureOverride("plugin1", "feature2", "not-a-bool")
+	_, err = cfg.IsConfigFeatureActivated("features.plugin1.feature2")
+	assert.ErrorContains(t, err, "error converting features.plugin1.feature2 entry 'not-a-bool' to boolean value")
+}
@@ -11,7 +11,8 @@ type Metadata struct {
 
 // ConfigMetadata to store any config related metadata or settings
 type ConfigMetadata struct {
-	// PatchStrategy patch strategy to determine merge of nodes in config file. Two ways of patch strategies are merge and replace
+	// PatchStrategy patch strategy to determine merge of nodes in config file, keyed by the path of the nodes.
+	// The patch strategies are replace, merge, merge:<merge key>, append, prepend and set-union
 	PatchStrategy map[string]string `json:"patchStrategy,omitempty" yaml:"patchStrategy,omitempty" mapstructure:"patchStrategy,omitempty"`
 	// Settings related to config
 	Settings map[string]string `json:"settings,omitempty" yaml:"settings,omitempty" mapstructure:"settings,omitempty"`
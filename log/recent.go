@@ -0,0 +1,55 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// recentLogsSize is the number of the most recent log lines kept in memory
+const recentLogsSize = 200
+
+var recentLogs = &logRing{size: recentLogsSize}
+
+// logRing keeps the most recent log lines in memory
+type logRing struct {
+	sync.Mutex
+	lines []string
+	size  int
+}
+
+// add adds the lines of the message, dropping the oldest lines once the ring is full
+func (r *logRing) add(msg []byte) {
+	r.Lock()
+	defer r.Unlock()
+	for _, line := range strings.Split(string(msg), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		r.lines = append(r.lines, line)
+	}
+	if len(r.lines) > r.size {
+		r.lines = append([]string(nil), r.lines[len(r.lines)-r.size:]...)
+	}
+}
+
+// last returns up to n of the most recent log lines, oldest first
+func (r *logRing) last(n int) []string {
+	r.Lock()
+	defer r.Unlock()
+	if n > len(r.lines) {
+		n = len(r.lines)
+	}
+	if n <= 0 {
+		return nil
+	}
+	return append([]string(nil), r.lines[len(r.lines)-n:]...)
+}
+
+// GetRecentLogs returns up to n of the most recent log lines of the process, oldest first.
+// The log lines are kept regardless of the verbosity, the output messages are not kept.
+func GetRecentLogs(n int) []string {
+	return recentLogs.last(n)
+}
@@ -0,0 +1,38 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/tj/assert"
+)
+
+func TestGetRecentLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	var stderr, stdout bytes.Buffer
+	SetStderr(&stderr)
+	SetStdout(&stdout)
+	defer SetStderr(nil)
+	defer SetStdout(nil)
+
+	for i := 0; i < recentLogsSize+5; i++ {
+		Infof("recent-log-%d", i)
+	}
+	V(9).Info("recent-log-verbose")
+	Outputf("recent-output")
+
+	lines := GetRecentLogs(2)
+	assert.Len(lines, 2)
+	assert.Contains(lines[0], fmt.Sprintf("recent-log-%d", recentLogsSize+4))
+	assert.Contains(lines[1], "recent-log-verbose")
+
+	lines = GetRecentLogs(recentLogsSize + 10)
+	assert.Len(lines, recentLogsSize)
+	assert.Contains(lines[0], "recent-log-6")
+	assert.Empty(GetRecentLogs(0))
+}
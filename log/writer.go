@@ -116,6 +116,11 @@ func (w *writer) SetStderr(writer io.Writer) {
 func (w *writer) Write(header, msg []byte, logEnabled bool, logVerbosity int32, logType string) (n int, err error) {
 	fullMsg := append(header, msg...) //nolint:gocritic
 
+	// Keep the recent logs in memory e.g. for the crash reports
+	if logType != string(LogTypeOUTPUT) {
+		recentLogs.add(fullMsg)
+	}
+
 	// Always write to the audit log so it captures everything
 	if w.auditFile != "" {
 		w.fileWriter(w.auditFile, fullMsg)
@@ -0,0 +1,176 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+const (
+	// SupportedContextTypeAnnotation is the annotation of a command specifying the comma separated
+	// context types the command applies to e.g. "kubernetes,tanzu". The commands inherit the context
+	// types of their parent command and the root command those of PluginDescriptor.SupportedContextType.
+	SupportedContextTypeAnnotation = "supportedContextType"
+
+	// ContextRequiredAnnotation is the annotation of a command specifying whether the command requires an
+	// active context of one of its supported context types, "true" or "false". The commands inherit the value
+	// of their parent command. When unset, an active context is required if PluginDescriptor.ContextRequired is set.
+	ContextRequiredAnnotation = "contextRequired"
+
+	// ExperimentalAnnotation is the annotation marking a command as experimental when set to "true"
+	ExperimentalAnnotation = "experimental"
+)
+
+// CommandInfo describes a command of the plugin, as exported by the hidden `commands` command
+type CommandInfo struct {
+	// Name of the command
+	Name string `json:"name" yaml:"name"`
+	// Path of the command from the root command of the plugin e.g. "cluster list"
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Use is the one-line usage of the command e.g. "get NAME"
+	Use string `json:"use" yaml:"use"`
+	// Args is the usage of the arguments of the command, taken from Use e.g. "NAME"
+	Args string `json:"args,omitempty" yaml:"args,omitempty"`
+	// Aliases of the command
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// Short description of the command
+	Short string `json:"short,omitempty" yaml:"short,omitempty"`
+	// Long description of the command
+	Long string `json:"long,omitempty" yaml:"long,omitempty"`
+	// Example of usage of the command
+	Example string `json:"example,omitempty" yaml:"example,omitempty"`
+	// Deprecated is the deprecation message of the command, empty if the command is not deprecated
+	Deprecated string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	// Hidden indicates whether the command is hidden
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	// Experimental indicates whether the command is experimental
+	Experimental bool `json:"experimental,omitempty" yaml:"experimental,omitempty"`
+	// SupportedContextType specifies the context types the command applies to, empty if the command applies to any
+	SupportedContextType []types.ContextType `json:"supportedContextType,omitempty" yaml:"supportedContextType,omitempty"`
+	// Annotations of the command
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// Flags of the command, excluding the flags inherited from the parent commands
+	Flags []FlagInfo `json:"flags,omitempty" yaml:"flags,omitempty"`
+	// Commands are the sub-commands of the command
+	Commands []*CommandInfo `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// FlagInfo describes a flag of a command
+type FlagInfo struct {
+	// Name of the flag
+	Name string `json:"name" yaml:"name"`
+	// Shorthand of the flag, empty if the flag has no shorthand
+	Shorthand string `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	// Type of the value of the flag e.g. string, bool, stringSlice
+	Type string `json:"type" yaml:"type"`
+	// Default value of the flag
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Usage of the flag
+	Usage string `json:"usage,omitempty" yaml:"usage,omitempty"`
+	// Deprecated is the deprecation message of the flag, empty if the flag is not deprecated
+	Deprecated string `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+	// ShorthandDeprecated is the deprecation message of the shorthand, empty if the shorthand is not deprecated
+	ShorthandDeprecated string `json:"shorthandDeprecated,omitempty" yaml:"shorthandDeprecated,omitempty"`
+	// Hidden indicates whether the flag is hidden
+	Hidden bool `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+	// Persistent indicates whether the flag is inherited by the sub-commands
+	Persistent bool `json:"persistent,omitempty" yaml:"persistent,omitempty"`
+	// Required indicates whether the flag is required
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+func newCommandsCmd(desc *PluginDescriptor) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "commands",
+		Short:  "Plugin command tree",
+		Hidden: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := getCommandInfo(cmd.Root(), desc.SupportedContextType)
+			b, err := json.Marshal(info)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// getCommandInfo returns the description of the command and of its sub-commands. The command inherits the
+// supported context types of its parent unless specified with the SupportedContextTypeAnnotation.
+func getCommandInfo(cmd *cobra.Command, supportedContextTypes []types.ContextType) *CommandInfo {
+	if v, ok := cmd.Annotations[SupportedContextTypeAnnotation]; ok {
+		supportedContextTypes = parseSupportedContextTypes(v)
+	}
+
+	info := &CommandInfo{
+		Name:                 cmd.Name(),
+		Use:                  cmd.Use,
+		Aliases:              cmd.Aliases,
+		Short:                cmd.Short,
+		Long:                 cmd.Long,
+		Example:              cmd.Example,
+		Deprecated:           cmd.Deprecated,
+		Hidden:               cmd.Hidden,
+		Experimental:         strings.EqualFold(cmd.Annotations[ExperimentalAnnotation], "true"),
+		SupportedContextType: supportedContextTypes,
+		Annotations:          cmd.Annotations,
+		Flags:                getFlagInfos(cmd),
+	}
+	if cmd.HasParent() {
+		info.Path = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().CommandPath()+" ")
+	}
+	if fields := strings.Fields(cmd.Use); len(fields) > 1 {
+		info.Args = strings.Join(fields[1:], " ")
+	}
+	for _, c := range cmd.Commands() {
+		info.Commands = append(info.Commands, getCommandInfo(c, supportedContextTypes))
+	}
+	return info
+}
+
+// getFlagInfos returns the description of the flags of the command sorted by name, excluding the inherited flags
+func getFlagInfos(cmd *cobra.Command) []FlagInfo {
+	var flags []FlagInfo
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		flags = append(flags, FlagInfo{
+			Name:                f.Name,
+			Shorthand:           f.Shorthand,
+			Type:                f.Value.Type(),
+			Default:             f.DefValue,
+			Usage:               f.Usage,
+			Deprecated:          f.Deprecated,
+			ShorthandDeprecated: f.ShorthandDeprecated,
+			Hidden:              f.Hidden,
+			Persistent:          cmd.PersistentFlags().Lookup(f.Name) != nil,
+			Required:            required,
+		})
+	})
+	sort.Slice(flags, func(i, j int) bool {
+		return flags[i].Name < flags[j].Name
+	})
+	return flags
+}
+
+// parseSupportedContextTypes returns the context types of the comma separated value
+func parseSupportedContextTypes(value string) []types.ContextType {
+	var contextTypes []types.ContextType
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			contextTypes = append(contextTypes, types.ContextType(v))
+		}
+	}
+	return contextTypes
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestCommands(t *testing.T) {
+	descriptor := PluginDescriptor{
+		Name:                 "cluster",
+		Target:               types.TargetK8s,
+		Description:          "Cluster operations",
+		Version:              "v1.2.3",
+		Group:                ManageCmdGroup,
+		SupportedContextType: []types.ContextType{types.ContextTypeK8s, types.ContextTypeTanzu},
+	}
+	p, err := NewPlugin(&descriptor)
+	require.NoError(t, err)
+
+	listCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the clusters",
+		RunE:    func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	listCmd.Flags().StringP("output", "o", "table", "Output format (yaml|json|table)")
+	listCmd.Flags().Bool("all", false, "List all the clusters")
+	_ = listCmd.Flags().MarkDeprecated("all", "use --all-namespaces instead")
+
+	getCmd := &cobra.Command{
+		Use:   "get NAME",
+		Short: "Get a cluster",
+		Annotations: map[string]string{
+			SupportedContextTypeAnnotation: "tanzu",
+			ExperimentalAnnotation:         "true",
+		},
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	getCmd.Flags().String("namespace", "", "Namespace of the cluster")
+	_ = getCmd.MarkFlagRequired("namespace")
+
+	p.Cmd.PersistentFlags().Bool("verbose", false, "Verbose output")
+	p.AddCommands(listCmd, getCmd)
+
+	var stdout bytes.Buffer
+	p.Cmd.SetOut(&stdout)
+	p.Cmd.SetArgs([]string{"commands"})
+	require.NoError(t, p.Execute())
+
+	var root CommandInfo
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &root))
+	assert.Equal(t, "cluster", root.Name)
+	assert.Equal(t, "", root.Path)
+	assert.Equal(t, descriptor.SupportedContextType, root.SupportedContextType)
+	assert.Equal(t, []FlagInfo{
+		{Name: "verbose", Type: "bool", Default: "false", Usage: "Verbose output", Persistent: true},
+	}, root.Flags)
+
+	commands := make(map[string]*CommandInfo)
+	for _, c := range root.Commands {
+		commands[c.Name] = c
+	}
+	for _, name := range []string{"commands", "describe", "info", "version", "lint", "post-install", "pre-uninstall", "post-upgrade", "context-changed", "generate-docs", "generate-manifest", "list", "get"} {
+		assert.Contains(t, commands, name)
+	}
+	assert.True(t, commands["commands"].Hidden)
+
+	list := commands["list"]
+	assert.Equal(t, "list", list.Path)
+	assert.Equal(t, []string{"ls"}, list.Aliases)
+	assert.Equal(t, "", list.Args)
+	assert.False(t, list.Experimental)
+	assert.Equal(t, descriptor.SupportedContextType, list.SupportedContextType)
+	assert.Equal(t, []FlagInfo{
+		{Name: "all", Type: "bool", Default: "false", Usage: "List all the clusters", Deprecated: "use --all-namespaces instead", Hidden: true},
+		{Name: "output", Shorthand: "o", Type: "string", Default: "table", Usage: "Output format (yaml|json|table)"},
+	}, list.Flags)
+
+	get := commands["get"]
+	assert.Equal(t, "get", get.Path)
+	assert.Equal(t, "NAME", get.Args)
+	assert.True(t, get.Experimental)
+	assert.Equal(t, []types.ContextType{types.ContextTypeTanzu}, get.SupportedContextType)
+	assert.Equal(t, []FlagInfo{{Name: "namespace", Type: "string", Usage: "Namespace of the cluster", Required: true}}, get.Flags)
+}
@@ -0,0 +1,115 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+// ErrCodeNoActiveContext is the code of the error returned when a command requires an active context
+// of a type that is not active
+const ErrCodeNoActiveContext = "NO_ACTIVE_CONTEXT"
+
+// newCheckActiveContextFunc returns the PersistentPreRunE of the root command of the plugin checking
+// the command is invoked with an active context of one of the supported context types of the command.
+// Note that cobra only runs the nearest PersistentPreRunE, the check is skipped for the commands
+// of the plugin defining their own PersistentPreRunE unless they call CheckActiveContext.
+func newCheckActiveContextFunc(desc *PluginDescriptor) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return checkActiveContext(cmd, desc.SupportedContextType, desc.ContextRequired)
+	}
+}
+
+// CheckActiveContext returns an error if the command requires an active context and no context of one of
+// the supported context types of the command is active. The supported context types are specified with the
+// SupportedContextTypeAnnotation of the command or of its parents, defaulting to the supportedContextTypes
+// of the plugin. The check is enabled with the ContextRequiredAnnotation set to "true".
+func CheckActiveContext(cmd *cobra.Command, supportedContextTypes []types.ContextType) error {
+	return checkActiveContext(cmd, supportedContextTypes, false)
+}
+
+// checkActiveContext checks the active context like CheckActiveContext, the active context being required
+// by default if contextRequired is true
+func checkActiveContext(cmd *cobra.Command, supportedContextTypes []types.ContextType, contextRequired bool) error {
+	if isCobraCommand(cmd) {
+		return nil
+	}
+	contextTypes, required := getContextRequirement(cmd, supportedContextTypes, contextRequired)
+	if !required {
+		return nil
+	}
+	activeContexts, err := config.GetAllActiveContextsMap()
+	if err != nil {
+		return pluginerrors.NewInternalError("failed to get the active contexts", pluginerrors.WithCause(err))
+	}
+	if len(contextTypes) == 0 && len(activeContexts) > 0 {
+		return nil
+	}
+	for _, contextType := range contextTypes {
+		if activeContexts[contextType] != nil {
+			return nil
+		}
+	}
+	return newNoActiveContextError(cmd, contextTypes)
+}
+
+// getContextRequirement returns the supported context types of the command and whether the command requires an
+// active context, pluginContextRequired if not annotated. The annotations of the command take precedence over
+// the annotations of its parents.
+func getContextRequirement(cmd *cobra.Command, pluginContextTypes []types.ContextType, pluginContextRequired bool) ([]types.ContextType, bool) {
+	var contextTypes []types.ContextType
+	var contextTypesFound bool
+	var contextRequired string
+	for c := cmd; c != nil; c = c.Parent() {
+		if v, ok := c.Annotations[SupportedContextTypeAnnotation]; ok && !contextTypesFound {
+			contextTypes, contextTypesFound = parseSupportedContextTypes(v), true
+		}
+		if v, ok := c.Annotations[ContextRequiredAnnotation]; ok && contextRequired == "" {
+			contextRequired = v
+		}
+	}
+	if !contextTypesFound {
+		contextTypes = pluginContextTypes
+	}
+	if contextRequired != "" {
+		return contextTypes, strings.EqualFold(contextRequired, "true")
+	}
+	return contextTypes, pluginContextRequired
+}
+
+// isCobraCommand returns true if the command is the help or a completion command added by cobra
+func isCobraCommand(cmd *cobra.Command) bool {
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Parent() != cmd.Root() {
+			continue
+		}
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+	}
+	return false
+}
+
+// newNoActiveContextError returns the error of a command invoked without an active context of its context types
+func newNoActiveContextError(cmd *cobra.Command, contextTypes []types.ContextType) error {
+	message := fmt.Sprintf("command %q requires an active context", cmd.CommandPath())
+	if len(contextTypes) > 0 {
+		names := make([]string, len(contextTypes))
+		for i, contextType := range contextTypes {
+			names[i] = string(contextType)
+		}
+		message += " of type " + strings.Join(names, " or ")
+	}
+	return pluginerrors.NewNotFoundError(message,
+		pluginerrors.WithCode(ErrCodeNoActiveContext),
+		pluginerrors.WithHint("Run 'tanzu context use <context>' to set the active context, or 'tanzu context list' to list the contexts."))
+}
@@ -0,0 +1,225 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+// setupSandboxedConfig points the tanzu configuration to temporary files with the config-ng.yaml content
+func setupSandboxedConfig(t *testing.T, cfgNextGen string) {
+	dir := t.TempDir()
+	for key, content := range map[string]string{
+		config.EnvConfigKey:         "",
+		config.EnvConfigNextGenKey:  cfgNextGen,
+		config.EnvConfigMetadataKey: "",
+	} {
+		path := filepath.Join(dir, key+".yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		t.Setenv(key, path)
+	}
+}
+
+func TestCheckActiveContext(t *testing.T) {
+	cfgK8sActive := `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+currentContext:
+  kubernetes: prod
+`
+	cfgK8sActiveWithTanzu := `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+  - name: staging
+    contextType: tanzu
+    clusterOpts:
+      context: staging-ctx
+      path: /tmp/kubeconfig
+currentContext:
+  kubernetes: prod
+`
+	tests := []struct {
+		name                 string
+		cfgNextGen           string
+		env                  string
+		supportedContextType []types.ContextType
+		contextRequired      bool
+		contextOverride      bool
+		annotations          map[string]string
+		args                 []string
+		err                  string
+	}{
+		{
+			name:       "no supported context type",
+			cfgNextGen: "",
+			args:       []string{"get"},
+		},
+		{
+			name:                 "active context of a supported type",
+			cfgNextGen:           cfgK8sActive,
+			supportedContextType: []types.ContextType{types.ContextTypeK8s, types.ContextTypeTanzu},
+			args:                 []string{"get"},
+		},
+		{
+			name:                 "no active context of a supported type",
+			cfgNextGen:           cfgK8sActive,
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu, types.ContextTypeTMC},
+			contextRequired:      true,
+			args:                 []string{"get"},
+			err:                  `command "test-plugin get" requires an active context of type tanzu or mission-control`,
+		},
+		{
+			name:                 "command supported context type overrides the plugin",
+			cfgNextGen:           cfgK8sActive,
+			supportedContextType: []types.ContextType{types.ContextTypeK8s},
+			contextRequired:      true,
+			annotations:          map[string]string{SupportedContextTypeAnnotation: "tanzu"},
+			args:                 []string{"get"},
+			err:                  `command "test-plugin get" requires an active context of type tanzu`,
+		},
+		{
+			name:                 "context override of a supported type",
+			cfgNextGen:           cfgK8sActiveWithTanzu,
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			contextOverride:      true,
+			args:                 []string{"get", "--context", "staging"},
+		},
+		{
+			name:                 "context override with TANZU_CONTEXT",
+			cfgNextGen:           cfgK8sActiveWithTanzu,
+			env:                  "staging",
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			contextOverride:      true,
+			args:                 []string{"get"},
+		},
+		{
+			name:                 "context override flag takes precedence over TANZU_CONTEXT",
+			cfgNextGen:           cfgK8sActiveWithTanzu,
+			env:                  "staging",
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			contextOverride:      true,
+			args:                 []string{"get", "--context", "prod"},
+			err:                  `command "test-plugin get" requires an active context of type tanzu`,
+		},
+		{
+			name:                 "context override of an unsupported type",
+			cfgNextGen:           cfgK8sActive,
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			contextOverride:      true,
+			args:                 []string{"get", "--context", "prod"},
+			err:                  `command "test-plugin get" requires an active context of type tanzu`,
+		},
+		{
+			name:                 "context not required by default",
+			cfgNextGen:           "",
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			args:                 []string{"get"},
+		},
+		{
+			name:                 "command opts in with a supported context type",
+			cfgNextGen:           cfgK8sActive,
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			annotations:          map[string]string{ContextRequiredAnnotation: "true"},
+			args:                 []string{"get"},
+			err:                  `command "test-plugin get" requires an active context of type tanzu`,
+		},
+		{
+			name:                 "command opts out",
+			cfgNextGen:           "",
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			annotations:          map[string]string{ContextRequiredAnnotation: "false"},
+			args:                 []string{"get"},
+		},
+		{
+			name:        "command opts in without supported context type",
+			cfgNextGen:  "",
+			annotations: map[string]string{ContextRequiredAnnotation: "true"},
+			args:        []string{"get"},
+			err:         `command "test-plugin get" requires an active context`,
+		},
+		{
+			name:        "command opts in with any active context",
+			cfgNextGen:  cfgK8sActive,
+			annotations: map[string]string{ContextRequiredAnnotation: "true"},
+			args:        []string{"get"},
+		},
+		{
+			name:                 "runtime commands do not require an active context",
+			cfgNextGen:           "",
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			args:                 []string{"version"},
+		},
+		{
+			name:                 "help command does not require an active context",
+			cfgNextGen:           "",
+			supportedContextType: []types.ContextType{types.ContextTypeTanzu},
+			contextRequired:      true,
+			args:                 []string{"help", "get"},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			setupSandboxedConfig(t, spec.cfgNextGen)
+			t.Setenv(EnvContextOverrideKey, spec.env)
+			t.Cleanup(config.ResetContextOverride)
+
+			p, err := NewPlugin(&PluginDescriptor{
+				Name:                 "test-plugin",
+				Target:               types.TargetK8s,
+				Description:          "Description of the plugin",
+				Version:              "v1.2.3",
+				Group:                ManageCmdGroup,
+				SupportedContextType: spec.supportedContextType,
+				ContextRequired:      spec.contextRequired,
+				ContextOverride:      spec.contextOverride,
+			})
+			require.NoError(t, err)
+			p.AddCommands(&cobra.Command{
+				Use:         "get",
+				Annotations: spec.annotations,
+				RunE:        func(cmd *cobra.Command, args []string) error { return nil },
+			})
+
+			var out bytes.Buffer
+			p.Cmd.SetOut(&out)
+			p.Cmd.SetErr(&out)
+			p.Cmd.SetArgs(spec.args)
+			err = p.Execute()
+			if spec.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, spec.err)
+			pluginErr := pluginerrors.FromError(err)
+			assert.Equal(t, pluginerrors.KindNotFound, pluginErr.Kind)
+			assert.Equal(t, ErrCodeNoActiveContext, pluginErr.Code)
+			assert.Contains(t, pluginErr.Hint, "tanzu context use")
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+)
+
+const (
+	// ContextOverrideFlagName is the name of the persistent flag overriding the active context for a single invocation
+	ContextOverrideFlagName = "context"
+
+	// EnvContextOverrideKey is the environment variable overriding the active context for the invocations of the plugin
+	EnvContextOverrideKey = "TANZU_CONTEXT"
+)
+
+// applyContextOverrideEnv overrides the active context with the context specified with the TANZU_CONTEXT
+// environment variable, if set. The --context flag parsed afterwards takes precedence.
+// The context is validated by validateContextOverride once the flags are parsed.
+func applyContextOverrideEnv() {
+	if name := os.Getenv(EnvContextOverrideKey); name != "" {
+		config.SetContextOverride(name)
+	}
+}
+
+// addContextOverrideFlag adds the persistent flag `--context name` to the root command of the plugin.
+// The flag overrides the active context for the current invocation without persisting it to the config,
+// taking precedence over the TANZU_CONTEXT environment variable.
+// The flag is not added if the plugin already defines a `--context` flag on its root command.
+func addContextOverrideFlag(cmd *cobra.Command) {
+	if cmd.PersistentFlags().Lookup(ContextOverrideFlagName) != nil || cmd.Flags().Lookup(ContextOverrideFlagName) != nil {
+		return
+	}
+	cmd.PersistentFlags().Var(&contextOverrideValue{}, ContextOverrideFlagName,
+		"Name of the context to use for this invocation instead of the active context")
+}
+
+// validateContextOverride returns a Usage error of the command if the context overriding the active context
+// does not exist. It is called once the flags of the command are parsed, so that only the context which takes
+// precedence between the --context flag and the TANZU_CONTEXT environment variable is validated.
+func validateContextOverride(cmd *cobra.Command) error {
+	name := config.GetContextOverride()
+	if name == "" {
+		return nil
+	}
+	if _, err := config.GetContext(name); err != nil {
+		return newUsageError(cmd, errors.Errorf("context %q specified with --%s or %s not found",
+			name, ContextOverrideFlagName, EnvContextOverrideKey))
+	}
+	return nil
+}
+
+// contextOverrideValue implements pflag.Value and overrides the active context as the flag value is parsed
+type contextOverrideValue struct {
+	name string
+}
+
+// Set overrides the active context with the named context
+func (v *contextOverrideValue) Set(val string) error {
+	if val == "" {
+		return errors.New("the context name cannot be empty")
+	}
+	config.SetContextOverride(val)
+	v.name = val
+	return nil
+}
+
+// String returns the name of the context specified
+func (v *contextOverrideValue) String() string {
+	return v.name
+}
+
+// Type returns the type of the flag value
+func (v *contextOverrideValue) Type() string {
+	return "string"
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+func TestContextOverride(t *testing.T) {
+	cfgNextGen := `contexts:
+  - name: prod
+    target: kubernetes
+    contextType: kubernetes
+    clusterOpts:
+      context: prod-ctx
+      path: /tmp/kubeconfig
+  - name: staging
+    contextType: tanzu
+    clusterOpts:
+      context: staging-ctx
+      path: /tmp/kubeconfig
+currentContext:
+  kubernetes: prod
+`
+	tests := []struct {
+		name            string
+		env             string
+		contextOverride bool
+		args            []string
+		context         string
+		err             string
+		kind            pluginerrors.Kind
+	}{
+		{
+			name:            "context override with the flag",
+			contextOverride: true,
+			args:            []string{"get", "--context", "staging"},
+			context:         "staging",
+		},
+		{
+			name:            "context override with TANZU_CONTEXT",
+			env:             "staging",
+			contextOverride: true,
+			args:            []string{"get"},
+			context:         "staging",
+		},
+		{
+			name:            "unknown context specified with the flag",
+			contextOverride: true,
+			args:            []string{"get", "--context", "unknown"},
+			err:             `context "unknown" specified with --context or TANZU_CONTEXT not found`,
+			kind:            pluginerrors.KindUsage,
+		},
+		{
+			name:            "unknown context specified with TANZU_CONTEXT",
+			env:             "unknown",
+			contextOverride: true,
+			args:            []string{"get"},
+			err:             `context "unknown" specified with --context or TANZU_CONTEXT not found`,
+			kind:            pluginerrors.KindUsage,
+		},
+		{
+			name:            "flag takes precedence over an unknown context specified with TANZU_CONTEXT",
+			env:             "unknown",
+			contextOverride: true,
+			args:            []string{"get", "--context", "staging"},
+			context:         "staging",
+		},
+		{
+			name: "flag not accepted without opt in",
+			args: []string{"get", "--context", "staging"},
+			err:  "unknown flag: --context",
+			kind: pluginerrors.KindUsage,
+		},
+		{
+			name: "TANZU_CONTEXT ignored without opt in",
+			env:  "unknown",
+			args: []string{"get"},
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			setupSandboxedConfig(t, cfgNextGen)
+			t.Setenv(EnvContextOverrideKey, spec.env)
+			t.Cleanup(config.ResetContextOverride)
+
+			p, err := NewPlugin(&PluginDescriptor{
+				Name:            "test-plugin",
+				Target:          types.TargetK8s,
+				Description:     "Description of the plugin",
+				Version:         "v1.2.3",
+				Group:           ManageCmdGroup,
+				ContextOverride: spec.contextOverride,
+			})
+			require.NoError(t, err)
+			var context string
+			p.AddCommands(&cobra.Command{
+				Use: "get",
+				RunE: func(cmd *cobra.Command, args []string) error {
+					context = config.GetContextOverride()
+					return nil
+				},
+			})
+
+			var out bytes.Buffer
+			p.Cmd.SetOut(&out)
+			p.Cmd.SetErr(&out)
+			p.Cmd.SetArgs(spec.args)
+			err = p.Execute()
+			if spec.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, spec.context, context)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), spec.err)
+			assert.Equal(t, spec.kind, pluginerrors.FromError(err).Kind)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+const (
+	// ErrCodePluginCrashed is the code of the error returned when a command of the plugin panics
+	ErrCodePluginCrashed = "PLUGIN_CRASHED"
+
+	// crashReportLogLines is the number of the most recent log lines included in the crash reports
+	crashReportLogLines = 50
+
+	// redactedValue replaces the secrets in the crash reports
+	redactedValue = "<redacted>"
+)
+
+var (
+	// sensitiveKeyPattern matches the names of the flags and of the keys whose values are secrets
+	sensitiveKeyPattern = `(?:password|passwd|passphrase|secret|token|api[-_]?key|access[-_]?key|private[-_]?key|credentials?)`
+
+	// sensitiveFlagRegexp matches the flags whose values are secrets e.g. --password or --api-token
+	sensitiveFlagRegexp = regexp.MustCompile(`(?i)^--?[\w-]*` + sensitiveKeyPattern + `[\w-]*$`)
+
+	// secretRegexps match the secrets in a text, the secret is the last group of the match
+	secretRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)([\w-]*` + sensitiveKeyPattern + `[\w-]*"?\s*[=:]\s*"?)([^\s"',;&]+)`),
+		regexp.MustCompile(`(?i)(authorization:\s*(?:bearer|basic)?\s*|bearer\s+)([^\s"',;]+)`),
+		regexp.MustCompile(`(://[^:/@\s]+:)([^@/\s]+)(@)`),
+	}
+)
+
+// crashReport is the report of a panic of a command of the plugin
+type crashReport struct {
+	plugin         string
+	version        string
+	runtimeVersion string
+	commandLine    []string
+	panicValue     interface{}
+	stack          []byte
+	logs           []string
+	time           time.Time
+}
+
+// newCrashReport returns the report of the panic with the secrets redacted
+func newCrashReport(desc *PluginDescriptor, panicValue interface{}, stack []byte) *crashReport {
+	report := &crashReport{
+		plugin:      desc.Name,
+		version:     desc.Version,
+		commandLine: redactArgs(os.Args),
+		panicValue:  panicValue,
+		stack:       stack,
+		logs:        log.GetRecentLogs(crashReportLogLines),
+		time:        time.Now(),
+	}
+	if _, ok := readBuildInfo(); ok {
+		report.runtimeVersion = getPluginRuntimeVersion()
+	}
+	return report
+}
+
+// String returns the report as text
+func (r *crashReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plugin: %s\n", r.plugin)
+	fmt.Fprintf(&b, "Version: %s\n", r.version)
+	fmt.Fprintf(&b, "Plugin runtime version: %s\n", r.runtimeVersion)
+	fmt.Fprintf(&b, "Go version: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Time: %s\n", r.time.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Command line: %s\n", strings.Join(r.commandLine, " "))
+	fmt.Fprintf(&b, "\nPanic: %s\n", redactSecrets(fmt.Sprint(r.panicValue)))
+	fmt.Fprintf(&b, "\nStack trace:\n%s\n", redactSecrets(string(r.stack)))
+	fmt.Fprintf(&b, "Recent logs:\n")
+	for _, line := range r.logs {
+		fmt.Fprintf(&b, "%s\n", redactSecrets(line))
+	}
+	return b.String()
+}
+
+// write writes the report in the crash reports directory and returns its path
+func (r *crashReport) write() (string, error) {
+	dir, err := config.GetTanzuCrashReportsDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the crash reports directory")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", errors.Wrap(err, "failed to create the crash reports directory")
+	}
+	name := fmt.Sprintf("%s-%s-%d.txt", r.plugin, r.time.UTC().Format("20060102T150405Z"), os.Getpid())
+	path := filepath.Join(dir, strings.ReplaceAll(name, string(filepath.Separator), "_"))
+	if err := os.WriteFile(path, []byte(r.String()), 0o600); err != nil {
+		return "", errors.Wrap(err, "failed to write the crash report")
+	}
+	return path, nil
+}
+
+// newCrashError writes the crash report of the panic and returns the error reporting the crash to the user
+func newCrashError(desc *PluginDescriptor, panicValue interface{}, stack []byte) *pluginerrors.Error {
+	msg := fmt.Sprintf("the %s plugin crashed: %s", desc.Name, redactSecrets(fmt.Sprint(panicValue)))
+	path, err := newCrashReport(desc, panicValue, stack).write()
+	if err != nil {
+		return pluginerrors.NewInternalError(msg, pluginerrors.WithCode(ErrCodePluginCrashed),
+			pluginerrors.WithHint(fmt.Sprintf("The crash report could not be written: %v", err)))
+	}
+	return pluginerrors.NewInternalError(msg, pluginerrors.WithCode(ErrCodePluginCrashed),
+		pluginerrors.WithHint(fmt.Sprintf("A crash report was written to %s, please attach it when reporting the issue.", path)))
+}
+
+// redactArgs returns the command line with the values of the sensitive flags redacted
+func redactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case i > 0 && sensitiveFlagRegexp.MatchString(args[i-1]):
+			redacted[i] = redactedValue
+		default:
+			redacted[i] = redactSecrets(arg)
+		}
+	}
+	return redacted
+}
+
+// redactSecrets returns the text with the secrets e.g. passwords, tokens and credentials of URLs redacted
+func redactSecrets(s string) string {
+	for _, re := range secretRegexps {
+		s = re.ReplaceAllString(s, "${1}"+redactedValue+"${3}")
+	}
+	return s
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+// outputFlagName is the name of the flag of the commands specifying the output format
+const outputFlagName = "output"
+
+// flagErrorFunc returns the errors of the flags parsing as Usage errors
+func flagErrorFunc(cmd *cobra.Command, err error) error {
+	return newUsageError(cmd, err)
+}
+
+// newUsageError returns the error as a Usage error suggesting the help of the command
+func newUsageError(cmd *cobra.Command, err error) *pluginerrors.Error {
+	return pluginerrors.NewUsageError("",
+		pluginerrors.WithCause(err),
+		pluginerrors.WithHint(fmt.Sprintf("Run '%s --help' for usage.", cmd.CommandPath())))
+}
+
+// toPluginError returns the error of the command as a typed plugin error. The errors of cobra failing to
+// find the sub-command are returned as Usage errors and the other errors which are not typed are returned
+// as Internal errors. The invalid flags and arguments are typed by flagErrorFunc and wrapArgsValidators.
+func toPluginError(cmd *cobra.Command, err error) *pluginerrors.Error {
+	var e *pluginerrors.Error
+	if errors.As(err, &e) {
+		return e
+	}
+	// A root command which is not runnable can only fail to find the sub-command,
+	// as cobra returns the help of the command instead of running it
+	if !cmd.HasParent() && !cmd.Runnable() {
+		return newUsageError(cmd, err)
+	}
+	return pluginerrors.FromError(err)
+}
+
+// wrapArgsValidators wraps the validators of the arguments of the command and of its sub-commands, so the
+// invalid arguments, the missing required flags and the unknown context overrides are returned as Usage errors. The wrapped validators also
+// record the command being executed in the plugin, for the crash errors. The commands already wrapped are skipped.
+func (p *Plugin) wrapArgsValidators(cmd *cobra.Command) {
+	if p.wrappedArgs == nil {
+		p.wrappedArgs = make(map[*cobra.Command]bool)
+	}
+	if !p.wrappedArgs[cmd] {
+		p.wrappedArgs[cmd] = true
+		validateArgs := cmd.Args
+		switch {
+		case validateArgs != nil:
+		case cmd.HasParent():
+			validateArgs = cobra.ArbitraryArgs
+		case cmd.Runnable():
+			validateArgs = legacyRootArgs
+		}
+		// cobra validates the sub-commands of a root command without validator while finding the
+		// command, these errors are typed by toPluginError
+		if validateArgs != nil {
+			cmd.Args = func(cmd *cobra.Command, args []string) error {
+				p.executedCmd = cmd
+				if err := validateArgs(cmd, args); err != nil {
+					return newUsageError(cmd, err)
+				}
+				// The required flags are validated before running the command so that they are reported as
+				// Usage errors. cobra validates them again later on.
+				if err := cmd.ValidateRequiredFlags(); err != nil {
+					return newUsageError(cmd, err)
+				}
+				if err := cmd.ValidateFlagGroups(); err != nil {
+					return newUsageError(cmd, err)
+				}
+				return validateContextOverride(cmd)
+			}
+		}
+	}
+	for _, c := range cmd.Commands() {
+		p.wrapArgsValidators(c)
+	}
+}
+
+// legacyRootArgs validates the arguments of a runnable root command without validator like cobra does
+// i.e. the arguments must not be unknown sub-commands
+func legacyRootArgs(cmd *cobra.Command, args []string) error {
+	if !cmd.HasSubCommands() || len(args) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+	if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 && !cmd.DisableSuggestions {
+		msg += "\n\nDid you mean this?\n"
+		for _, s := range suggestions {
+			msg += fmt.Sprintf("\t%v\n", s)
+		}
+	}
+	return errors.New(msg)
+}
+
+// printUsage prints the usage of the command after its Usage error, unless the error is rendered as JSON
+func printUsage(cmd *cobra.Command, err *pluginerrors.Error) {
+	if err.Kind != pluginerrors.KindUsage || strings.EqualFold(getOutputFormat(cmd), "json") {
+		return
+	}
+	cmd.PrintErrln()
+	cmd.PrintErr(cmd.UsageString())
+}
+
+// getOutputFormat returns the value of the output flag of the command, empty if the command has no output flag
+func getOutputFormat(cmd *cobra.Command) string {
+	if f := cmd.Flags().Lookup(outputFlagName); f != nil {
+		return f.Value.String()
+	}
+	return ""
+}
@@ -0,0 +1,249 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package errors provides the typed errors returned by plugin commands. The typed errors are mapped to
+// stable exit codes and rendered consistently by the plugin, so the CLI and scripts can tell a usage error
+// from an authentication failure, a missing resource or a transient failure.
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Kind is the class of a plugin error
+type Kind string
+
+const (
+	// KindUsage indicates the command is invoked with invalid arguments or flags
+	KindUsage Kind = "Usage"
+	// KindNotFound indicates a resource does not exist
+	KindNotFound Kind = "NotFound"
+	// KindUnauthorized indicates the user is not authenticated or not allowed to perform the operation
+	KindUnauthorized Kind = "Unauthorized"
+	// KindConflict indicates the operation conflicts with the current state of a resource
+	KindConflict Kind = "Conflict"
+	// KindUnavailable indicates a transient failure e.g. a network error, the operation can be retried
+	KindUnavailable Kind = "Unavailable"
+	// KindInternal indicates an unexpected failure, this is the kind of the errors which are not typed
+	KindInternal Kind = "Internal"
+)
+
+// The exit codes of the plugin errors. These values are part of the plugin contract and must not change.
+const (
+	// ExitCodeOK is the exit code of a successful command
+	ExitCodeOK = 0
+	// ExitCodeInternal is the exit code of the Internal errors and of the errors which are not typed
+	ExitCodeInternal = 1
+	// ExitCodeUsage is the exit code of the Usage errors
+	ExitCodeUsage = 2
+	// ExitCodeNotFound is the exit code of the NotFound errors
+	ExitCodeNotFound = 3
+	// ExitCodeUnauthorized is the exit code of the Unauthorized errors
+	ExitCodeUnauthorized = 4
+	// ExitCodeConflict is the exit code of the Conflict errors
+	ExitCodeConflict = 5
+	// ExitCodeUnavailable is the exit code of the Unavailable errors
+	ExitCodeUnavailable = 6
+)
+
+// exitCodes maps the kinds of errors to their exit codes
+var exitCodes = map[Kind]int{
+	KindUsage:        ExitCodeUsage,
+	KindNotFound:     ExitCodeNotFound,
+	KindUnauthorized: ExitCodeUnauthorized,
+	KindConflict:     ExitCodeConflict,
+	KindUnavailable:  ExitCodeUnavailable,
+	KindInternal:     ExitCodeInternal,
+}
+
+// Error is a typed plugin error
+type Error struct {
+	// Kind is the class of the error
+	Kind Kind `json:"kind" yaml:"kind"`
+	// Code is an optional machine readable code identifying the error e.g. CONTEXT_NOT_FOUND
+	Code string `json:"code,omitempty" yaml:"code,omitempty"`
+	// Message describes the error
+	Message string `json:"message" yaml:"message"`
+	// Hint is an optional suggestion to resolve the error
+	Hint string `json:"hint,omitempty" yaml:"hint,omitempty"`
+	// DocsURL is an optional link to the documentation of the error
+	DocsURL string `json:"docsURL,omitempty" yaml:"docsURL,omitempty"`
+	// Err is the optional underlying error
+	Err error `json:"-" yaml:"-"`
+}
+
+// Option is a function type that applies optional fields to an Error.
+type Option func(e *Error)
+
+// WithCode returns an Option function that sets the code of the error.
+func WithCode(code string) Option {
+	return func(e *Error) {
+		e.Code = code
+	}
+}
+
+// WithHint returns an Option function that sets the hint of the error.
+func WithHint(hint string) Option {
+	return func(e *Error) {
+		e.Hint = hint
+	}
+}
+
+// WithDocsURL returns an Option function that sets the documentation link of the error.
+func WithDocsURL(url string) Option {
+	return func(e *Error) {
+		e.DocsURL = url
+	}
+}
+
+// WithCause returns an Option function that sets the underlying error of the error.
+func WithCause(err error) Option {
+	return func(e *Error) {
+		e.Err = err
+	}
+}
+
+// New returns an error of the kind with the message
+func New(kind Kind, message string, opts ...Option) *Error {
+	e := &Error{Kind: kind, Message: message}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// NewUsageError returns a Usage error
+func NewUsageError(message string, opts ...Option) *Error {
+	return New(KindUsage, message, opts...)
+}
+
+// NewNotFoundError returns a NotFound error
+func NewNotFoundError(message string, opts ...Option) *Error {
+	return New(KindNotFound, message, opts...)
+}
+
+// NewUnauthorizedError returns an Unauthorized error
+func NewUnauthorizedError(message string, opts ...Option) *Error {
+	return New(KindUnauthorized, message, opts...)
+}
+
+// NewConflictError returns a Conflict error
+func NewConflictError(message string, opts ...Option) *Error {
+	return New(KindConflict, message, opts...)
+}
+
+// NewUnavailableError returns an Unavailable error
+func NewUnavailableError(message string, opts ...Option) *Error {
+	return New(KindUnavailable, message, opts...)
+}
+
+// NewInternalError returns an Internal error
+func NewInternalError(message string, opts ...Option) *Error {
+	return New(KindInternal, message, opts...)
+}
+
+// Error returns the message of the error followed by the message of the underlying error
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Message
+	case e.Message == "":
+		return e.Err.Error()
+	default:
+		return e.Message + ": " + e.Err.Error()
+	}
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the kind of the error
+func (e *Error) ExitCode() int {
+	if code, ok := exitCodes[e.Kind]; ok {
+		return code
+	}
+	return ExitCodeInternal
+}
+
+// FromError returns the typed error in the chain of the error. The errors which are not typed are
+// returned as Internal errors. Returns nil if the error is nil.
+func FromError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Kind: KindInternal, Err: err}
+}
+
+// KindOf returns the kind of the typed error in the chain of the error, Internal if the error is not typed
+// and empty if the error is nil
+func KindOf(err error) Kind {
+	if e := FromError(err); e != nil {
+		return e.Kind
+	}
+	return ""
+}
+
+// ExitCode returns the exit code of the error: ExitCodeOK if the error is nil, the exit code of
+// its kind if the error is typed and ExitCodeInternal otherwise
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	return FromError(err).ExitCode()
+}
+
+// renderedError is the JSON representation of an error
+type renderedError struct {
+	Kind     Kind   `json:"kind"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+	DocsURL  string `json:"docsURL,omitempty"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// Render writes the error to the writer. The format is either json or text (any other value):
+//
+//	Error: <message>
+//	Hint: <hint>
+//	Docs: <docs url>
+func Render(w io.Writer, err error, format string) error {
+	e := FromError(err)
+	if e == nil {
+		return nil
+	}
+	if strings.EqualFold(format, "json") {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(renderedError{
+			Kind:     e.Kind,
+			Code:     e.Code,
+			Message:  e.Error(),
+			Hint:     e.Hint,
+			DocsURL:  e.DocsURL,
+			ExitCode: e.ExitCode(),
+		})
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Error: %s\n", e.Error())
+	if e.Hint != "" {
+		fmt.Fprintf(&sb, "Hint: %s\n", e.Hint)
+	}
+	if e.DocsURL != "" {
+		fmt.Fprintf(&sb, "Docs: %s\n", e.DocsURL)
+	}
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		kind     Kind
+		exitCode int
+	}{
+		{name: "nil", err: nil, kind: "", exitCode: ExitCodeOK},
+		{name: "untyped", err: errors.New("boom"), kind: KindInternal, exitCode: ExitCodeInternal},
+		{name: "usage", err: NewUsageError("bad flag"), kind: KindUsage, exitCode: ExitCodeUsage},
+		{name: "not found", err: NewNotFoundError("no context"), kind: KindNotFound, exitCode: ExitCodeNotFound},
+		{name: "unauthorized", err: NewUnauthorizedError("expired token"), kind: KindUnauthorized, exitCode: ExitCodeUnauthorized},
+		{name: "conflict", err: NewConflictError("already exists"), kind: KindConflict, exitCode: ExitCodeConflict},
+		{name: "unavailable", err: NewUnavailableError("timeout"), kind: KindUnavailable, exitCode: ExitCodeUnavailable},
+		{name: "internal", err: NewInternalError("bug"), kind: KindInternal, exitCode: ExitCodeInternal},
+		{name: "wrapped", err: errors.Wrap(NewNotFoundError("no context"), "login"), kind: KindNotFound, exitCode: ExitCodeNotFound},
+		{name: "unknown kind", err: New("Teapot", "short and stout"), kind: "Teapot", exitCode: ExitCodeInternal},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			assert.Equal(t, spec.kind, KindOf(spec.err))
+			assert.Equal(t, spec.exitCode, ExitCode(spec.err))
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := NewUnavailableError("failed to reach the endpoint", WithCause(cause), WithCode("ENDPOINT_UNAVAILABLE"))
+	assert.EqualError(t, err, "failed to reach the endpoint: connection refused")
+	assert.True(t, errors.Is(err, cause))
+	assert.Equal(t, "ENDPOINT_UNAVAILABLE", err.Code)
+
+	assert.EqualError(t, NewUsageError("", WithCause(cause)), "connection refused")
+	assert.Nil(t, FromError(nil))
+	assert.Equal(t, &Error{Kind: KindInternal, Err: cause}, FromError(cause))
+}
+
+func TestRender(t *testing.T) {
+	err := errors.Wrap(NewNotFoundError("context \"prod\" not found",
+		WithCode("CONTEXT_NOT_FOUND"),
+		WithHint("Run 'tanzu context list' to list the contexts."),
+		WithDocsURL("https://docs.example.com/contexts")), "failed to login")
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, err, "text"))
+	assert.Equal(t, `Error: context "prod" not found
+Hint: Run 'tanzu context list' to list the contexts.
+Docs: https://docs.example.com/contexts
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, errors.New("boom"), ""))
+	assert.Equal(t, "Error: boom\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, err, "JSON"))
+	var rendered map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rendered))
+	assert.Equal(t, map[string]interface{}{
+		"kind":     "NotFound",
+		"code":     "CONTEXT_NOT_FOUND",
+		"message":  "context \"prod\" not found",
+		"hint":     "Run 'tanzu context list' to list the contexts.",
+		"docsURL":  "https://docs.example.com/contexts",
+		"exitCode": float64(ExitCodeNotFound),
+	}, rendered)
+
+	buf.Reset()
+	require.NoError(t, Render(&buf, nil, "json"))
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// FeatureOverrideFlagName is the name of the persistent flag overriding feature flags for a single invocation
+const FeatureOverrideFlagName = "feature"
+
+// AddFeatureOverrideFlag adds the hidden persistent flag `--feature key=value` to the plugin root command.
+// The flag overrides the feature flag for the current invocation without persisting it to the config and
+// can be repeated to override multiple feature flags.
+// The key is either the name of a feature flag of the plugin, or <plugin>.<key> (optionally prefixed
+// by "features.") to override the feature flag of another plugin e.g. `--feature global.context-aware-cli=true`
+func (p *Plugin) AddFeatureOverrideFlag() {
+	p.Cmd.PersistentFlags().Var(&featureOverrideValue{plugin: p.Cmd.Use}, FeatureOverrideFlagName, "Override a feature flag for this invocation (key=value)")
+	_ = p.Cmd.PersistentFlags().MarkHidden(FeatureOverrideFlagName)
+}
+
+// featureOverrideValue implements pflag.Value and overrides the feature flags as the flag values are parsed
+type featureOverrideValue struct {
+	plugin string
+	values []string
+}
+
+// Set parses the key=value and overrides the feature flag
+func (v *featureOverrideValue) Set(val string) error {
+	key, value, found := strings.Cut(val, "=")
+	if !found || key == "" || value == "" {
+		return errors.Errorf("invalid feature override %q, expected key=value", val)
+	}
+	plugin := v.plugin
+	key = strings.TrimPrefix(key, "features.")
+	if p, k, found := strings.Cut(key, "."); found {
+		plugin, key = p, k
+	}
+	if plugin == "" || key == "" {
+		return errors.Errorf("invalid feature override %q, expected key=value", val)
+	}
+	types.SetFeatureOverride(plugin, key, value)
+	v.values = append(v.values, val)
+	return nil
+}
+
+// String returns the feature overrides specified so far
+func (v *featureOverrideValue) String() string {
+	return fmt.Sprintf("[%s]", strings.Join(v.values, ","))
+}
+
+// Type returns the type of the flag value
+func (v *featureOverrideValue) Type() string {
+	return "stringArray"
+}
@@ -0,0 +1,54 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestAddFeatureOverrideFlag(t *testing.T) {
+	defer types.ResetFeatureOverrides()
+
+	descriptor := PluginDescriptor{
+		Name:        "override-plugin",
+		Target:      types.TargetGlobal,
+		Description: "Description of the plugin",
+		Version:     "v1.2.3",
+		Group:       "TestGroup",
+	}
+	p, err := NewPlugin(&descriptor)
+	assert.NoError(t, err)
+	p.AddFeatureOverrideFlag()
+
+	p.AddCommands(&cobra.Command{
+		Use: "sub",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	})
+
+	flag := p.Cmd.PersistentFlags().Lookup(FeatureOverrideFlagName)
+	assert.NotNil(t, flag)
+	assert.True(t, flag.Hidden)
+
+	p.Cmd.SetArgs([]string{"sub", "--feature", "dual-stack=true", "--feature", "features.global.context-aware-cli=false"})
+	err = p.Execute()
+	assert.NoError(t, err)
+
+	val, ok := types.GetFeatureOverride("override-plugin", "dual-stack")
+	assert.True(t, ok)
+	assert.Equal(t, "true", val)
+	val, ok = types.GetFeatureOverride("global", "context-aware-cli")
+	assert.True(t, ok)
+	assert.Equal(t, "false", val)
+
+	p.Cmd.SetArgs([]string{"sub", "--feature", "dual-stack"})
+	err = p.Execute()
+	assert.ErrorContains(t, err, "invalid feature override \"dual-stack\", expected key=value")
+}
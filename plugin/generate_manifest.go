@@ -0,0 +1,190 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+const (
+	// CLIPluginAPIVersion is the API version of the CLIPlugin resources consumed by the discovery sources
+	CLIPluginAPIVersion = "cli.tanzu.vmware.com/v1alpha1"
+	// CLIPluginKind is the kind of the CLIPlugin resources consumed by the discovery sources
+	CLIPluginKind = "CLIPlugin"
+
+	// artifactTypeOCI is the type of the artifacts of the plugins published as OCI images
+	artifactTypeOCI = "oci"
+	// artifactTypeLocal is the type of the artifacts of the plugins published on the local file system
+	artifactTypeLocal = "local"
+)
+
+// cliPlugin is the CLIPlugin resource describing a plugin and its artifacts to the discovery sources
+type cliPlugin struct {
+	APIVersion string            `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string            `json:"kind" yaml:"kind"`
+	Metadata   cliPluginMetadata `json:"metadata" yaml:"metadata"`
+	Spec       cliPluginSpec     `json:"spec" yaml:"spec"`
+}
+
+type cliPluginMetadata struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+type cliPluginSpec struct {
+	// Description is the plugin's description.
+	Description string `json:"description" yaml:"description"`
+	// RecommendedVersion is the version that Tanzu CLI should use if available.
+	RecommendedVersion string `json:"recommendedVersion" yaml:"recommendedVersion"`
+	// Target specifies the target of the plugin.
+	Target types.Target `json:"target,omitempty" yaml:"target,omitempty"`
+	// Artifacts contains the artifacts of the plugin by version.
+	Artifacts map[string][]cliPluginArtifact `json:"artifacts" yaml:"artifacts"`
+}
+
+// cliPluginArtifact is the plugin binary of an operating system and architecture
+type cliPluginArtifact struct {
+	// Image is the OCI image of the plugin binary, for the artifacts of type oci.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	// URI is the location of the plugin binary, for the artifacts of type local.
+	URI string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	// Type of the artifact, oci or local.
+	Type string `json:"type" yaml:"type"`
+	// Digest is the SHA256 of the plugin binary.
+	Digest string `json:"digest,omitempty" yaml:"digest,omitempty"`
+	// OS of the plugin binary.
+	OS string `json:"os" yaml:"os"`
+	// Arch is the architecture of the plugin binary.
+	Arch string `json:"arch" yaml:"arch"`
+}
+
+// pluginManifest is the plugin-manifest.yaml listing the plugins published together
+type pluginManifest struct {
+	Plugins []pluginManifestEntry `json:"plugins" yaml:"plugins"`
+}
+
+// pluginManifestEntry is the entry of a plugin in the plugin-manifest.yaml
+type pluginManifestEntry struct {
+	Name                 string              `json:"name" yaml:"name"`
+	Target               types.Target        `json:"target" yaml:"target"`
+	Description          string              `json:"description" yaml:"description"`
+	Versions             []string            `json:"versions" yaml:"versions"`
+	InvokedAs            []string            `json:"invokedAs,omitempty" yaml:"invokedAs,omitempty"`
+	SupportedContextType []types.ContextType `json:"supportedContextType,omitempty" yaml:"supportedContextType,omitempty"`
+}
+
+// generateManifestOptions are the options of the generate-manifest command
+type generateManifestOptions struct {
+	digestFrom string
+	image      string
+	uri        string
+}
+
+func newGenerateManifestCmd(desc *PluginDescriptor) *cobra.Command {
+	opts := &generateManifestOptions{}
+	cmd := &cobra.Command{
+		Use:   "generate-manifest",
+		Short: "Generate the CLIPlugin resource and the plugin-manifest.yaml entry of the plugin",
+		Long: `Generate the CLIPlugin resource and the plugin-manifest.yaml entry of the plugin from its descriptor.
+The artifact of the CLIPlugin resource is the plugin binary for the operating system and architecture
+the plugin is built for. The two YAML documents are written to stdout.`,
+		Hidden:      true,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{cliCommandAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			digest := ""
+			if opts.digestFrom != "" {
+				var err error
+				if digest, err = computeFileSHA256(opts.digestFrom); err != nil {
+					return err
+				}
+			}
+			return writeManifest(cmd.OutOrStdout(), newCLIPlugin(desc, digest, opts), newPluginManifest(desc))
+		},
+	}
+	cmd.Flags().StringVar(&opts.digestFrom, "digest-from", "", "Path of the plugin binary to compute the SHA256 digest of the artifact from")
+	cmd.Flags().StringVar(&opts.image, "image", "", "OCI image of the plugin binary, the artifact is of type oci")
+	cmd.Flags().StringVar(&opts.uri, "uri", "", "Location of the plugin binary, the artifact is of type local")
+	cmd.MarkFlagsOneRequired("image", "uri")
+	cmd.MarkFlagsMutuallyExclusive("image", "uri")
+
+	return cmd
+}
+
+// newCLIPlugin returns the CLIPlugin resource of the plugin with the artifact of the running plugin binary
+func newCLIPlugin(desc *PluginDescriptor, digest string, opts *generateManifestOptions) *cliPlugin {
+	artifact := cliPluginArtifact{
+		Image:  opts.image,
+		URI:    opts.uri,
+		Type:   artifactTypeOCI,
+		Digest: digest,
+		OS:     runtime.GOOS,
+		Arch:   runtime.GOARCH,
+	}
+	if opts.uri != "" {
+		artifact.Type = artifactTypeLocal
+	}
+	return &cliPlugin{
+		APIVersion: CLIPluginAPIVersion,
+		Kind:       CLIPluginKind,
+		Metadata:   cliPluginMetadata{Name: desc.Name},
+		Spec: cliPluginSpec{
+			Description:        desc.Description,
+			RecommendedVersion: desc.Version,
+			Target:             desc.Target,
+			Artifacts:          map[string][]cliPluginArtifact{desc.Version: {artifact}},
+		},
+	}
+}
+
+// newPluginManifest returns the plugin-manifest.yaml with the entry of the plugin
+func newPluginManifest(desc *PluginDescriptor) *pluginManifest {
+	return &pluginManifest{
+		Plugins: []pluginManifestEntry{{
+			Name:                 desc.Name,
+			Target:               desc.Target,
+			Description:          desc.Description,
+			Versions:             []string{desc.Version},
+			InvokedAs:            desc.InvokedAs,
+			SupportedContextType: desc.SupportedContextType,
+		}},
+	}
+}
+
+// writeManifest writes the CLIPlugin resource and the plugin manifest as YAML documents
+func writeManifest(w io.Writer, plugin *cliPlugin, manifest *pluginManifest) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(plugin); err != nil {
+		return errors.Wrap(err, "failed to encode the CLIPlugin resource")
+	}
+	if err := encoder.Encode(manifest); err != nil {
+		return errors.Wrap(err, "failed to encode the plugin manifest")
+	}
+	return encoder.Close()
+}
+
+// computeFileSHA256 returns the hex encoded SHA256 of the content of the file
+func computeFileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute the digest")
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", errors.Wrapf(err, "failed to compute the digest of %q", path)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
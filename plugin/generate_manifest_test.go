@@ -0,0 +1,133 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+func TestGenerateManifest(t *testing.T) {
+	descriptor := PluginDescriptor{
+		Name:                 "cluster",
+		Target:               types.TargetK8s,
+		Description:          "Cluster operations",
+		Version:              "v1.2.3",
+		Group:                ManageCmdGroup,
+		InvokedAs:            []string{"operations cluster"},
+		SupportedContextType: []types.ContextType{types.ContextTypeTanzu},
+	}
+
+	binary := filepath.Join(t.TempDir(), "tanzu-plugin-cluster")
+	require.NoError(t, os.WriteFile(binary, []byte("hello"), 0o600))
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		err      string
+	}{
+		{
+			name: "with digest and image",
+			args: []string{"--digest-from", binary, "--image", "registry.example.com/plugins/cluster:v1.2.3"},
+			expected: fmt.Sprintf(`apiVersion: cli.tanzu.vmware.com/v1alpha1
+kind: CLIPlugin
+metadata:
+  name: cluster
+spec:
+  description: Cluster operations
+  recommendedVersion: v1.2.3
+  target: kubernetes
+  artifacts:
+    v1.2.3:
+      - image: registry.example.com/plugins/cluster:v1.2.3
+        type: oci
+        digest: 2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
+        os: %s
+        arch: %s
+---
+plugins:
+  - name: cluster
+    target: kubernetes
+    description: Cluster operations
+    versions:
+      - v1.2.3
+    invokedAs:
+      - operations cluster
+    supportedContextType:
+      - tanzu
+`, runtime.GOOS, runtime.GOARCH),
+		},
+		{
+			name: "with local uri",
+			args: []string{"--uri", "/tmp/plugins/cluster"},
+			expected: fmt.Sprintf(`apiVersion: cli.tanzu.vmware.com/v1alpha1
+kind: CLIPlugin
+metadata:
+  name: cluster
+spec:
+  description: Cluster operations
+  recommendedVersion: v1.2.3
+  target: kubernetes
+  artifacts:
+    v1.2.3:
+      - uri: /tmp/plugins/cluster
+        type: local
+        os: %s
+        arch: %s
+---
+plugins:
+  - name: cluster
+    target: kubernetes
+    description: Cluster operations
+    versions:
+      - v1.2.3
+    invokedAs:
+      - operations cluster
+    supportedContextType:
+      - tanzu
+`, runtime.GOOS, runtime.GOARCH),
+		},
+		{
+			name: "image and uri",
+			args: []string{"--uri", "/tmp/plugins/cluster", "--image", "registry.example.com/plugins/cluster:v1.2.3"},
+			err:  "if any flags in the group [image uri] are set none of the others can be",
+		},
+		{
+			name: "neither image nor uri",
+			args: []string{"--digest-from", binary},
+			err:  "at least one of the flags in the group [image uri] is required",
+		},
+		{
+			name: "missing binary",
+			args: []string{"--digest-from", filepath.Join(t.TempDir(), "missing"), "--uri", "/tmp/plugins/cluster"},
+			err:  "failed to compute the digest",
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			cmd := newGenerateManifestCmd(&descriptor)
+			cmd.SetOut(&stdout)
+			cmd.SetErr(&stdout)
+			cmd.SetArgs(spec.args)
+			err := cmd.Execute()
+			if spec.err != "" {
+				assert.ErrorContains(t, err, spec.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, spec.expected, stdout.String())
+		})
+	}
+}
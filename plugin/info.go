@@ -10,6 +10,8 @@ import (
 	"runtime/debug"
 
 	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/buildinfo"
 )
 
 const (
@@ -30,33 +32,135 @@ type pluginInfo struct {
 	// This information can prove useful on Darwin (MacOS) ARM64 machine
 	// which can also execute AMD64 binaries in the Rosetta emulator.
 	BinaryArch string `json:"binaryArch" yaml:"binaryArch"`
+
+	// BuildInfo describes how the plugin binary was built.
+	BuildInfo *pluginBuildInfo `json:"buildInfo,omitempty" yaml:"buildInfo,omitempty"`
+}
+
+// pluginBuildInfo describes the build of a plugin binary. It combines the variables of the buildinfo
+// package set at build time with the build information embedded in the binary by the Go toolchain.
+type pluginBuildInfo struct {
+	// Date is the date the plugin binary was built, see buildinfo.Date
+	Date string `json:"date,omitempty" yaml:"date,omitempty"`
+	// SHA is the git commit SHA the plugin binary was built with, see buildinfo.SHA
+	SHA string `json:"sha,omitempty" yaml:"sha,omitempty"`
+	// Version is the version of the plugin built, see buildinfo.Version
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// GoVersion is the version of the Go toolchain that built the binary
+	GoVersion string `json:"goVersion,omitempty" yaml:"goVersion,omitempty"`
+	// OS is the operating system the binary was built for
+	OS string `json:"os" yaml:"os"`
+	// MainModule is the module containing the main package of the binary
+	MainModule *moduleInfo `json:"mainModule,omitempty" yaml:"mainModule,omitempty"`
+	// VCS describes the version control revision the binary was built from, when embedded by the Go toolchain
+	VCS *vcsInfo `json:"vcs,omitempty" yaml:"vcs,omitempty"`
+	// Dependencies are the Go modules the binary depends on
+	Dependencies []*moduleInfo `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+}
+
+// moduleInfo describes a Go module of a plugin binary
+type moduleInfo struct {
+	Path    string      `json:"path" yaml:"path"`
+	Version string      `json:"version,omitempty" yaml:"version,omitempty"`
+	Sum     string      `json:"sum,omitempty" yaml:"sum,omitempty"`
+	Replace *moduleInfo `json:"replace,omitempty" yaml:"replace,omitempty"`
 }
 
+// vcsInfo describes the version control revision a plugin binary was built from
+type vcsInfo struct {
+	System   string `json:"system" yaml:"system"`
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
+	Time     string `json:"time,omitempty" yaml:"time,omitempty"`
+	Modified bool   `json:"modified" yaml:"modified"`
+}
+
+// readBuildInfo returns the build information embedded in the running binary
+var readBuildInfo = debug.ReadBuildInfo
+
 func newInfoCmd(desc *PluginDescriptor) *cobra.Command {
+	var sbomFormat string
 	cmd := &cobra.Command{
 		Use:    "info",
 		Short:  "Plugin info",
 		Hidden: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			pi := pluginInfo{
-				PluginDescriptor:     *desc,
-				PluginRuntimeVersion: getPluginRuntimeVersion(),
-				BinaryArch:           runtime.GOARCH,
+			var v interface{}
+			switch sbomFormat {
+			case "":
+				v = pluginInfo{
+					PluginDescriptor:     *desc,
+					PluginRuntimeVersion: getPluginRuntimeVersion(),
+					BinaryArch:           runtime.GOARCH,
+					BuildInfo:            getPluginBuildInfo(),
+				}
+			case SBOMFormatCycloneDX:
+				v = newCycloneDXSBOM(desc, getPluginBuildInfo())
+			case SBOMFormatSPDX:
+				v = newSPDXSBOM(desc, getPluginBuildInfo())
+			default:
+				return fmt.Errorf("unsupported SBOM format %q, supported formats are %s and %s", sbomFormat, SBOMFormatCycloneDX, SBOMFormatSPDX)
 			}
-			b, err := json.Marshal(pi)
+			b, err := json.Marshal(v)
 			if err != nil {
 				return err
 			}
-			fmt.Println(string(b))
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
 			return nil
 		},
 	}
+	cmd.Flags().StringVar(&sbomFormat, "sbom", "", fmt.Sprintf("Output the software bill of materials of the Go modules of the plugin binary instead (%s|%s)", SBOMFormatCycloneDX, SBOMFormatSPDX))
 
 	return cmd
 }
 
+// getPluginBuildInfo returns the build information of the plugin binary
+func getPluginBuildInfo() *pluginBuildInfo {
+	info := &pluginBuildInfo{
+		Date:    buildinfo.Date,
+		SHA:     buildinfo.SHA,
+		Version: buildinfo.Version,
+		OS:      runtime.GOOS,
+	}
+	bi, ok := readBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	if bi.Main.Path != "" {
+		info.MainModule = toModuleInfo(&bi.Main)
+	}
+	settings := make(map[string]string)
+	for _, setting := range bi.Settings {
+		settings[setting.Key] = setting.Value
+	}
+	if system, ok := settings["vcs"]; ok {
+		info.VCS = &vcsInfo{
+			System:   system,
+			Revision: settings["vcs.revision"],
+			Time:     settings["vcs.time"],
+			Modified: settings["vcs.modified"] == "true",
+		}
+	}
+	for _, dep := range bi.Deps {
+		info.Dependencies = append(info.Dependencies, toModuleInfo(dep))
+	}
+	return info
+}
+
+func toModuleInfo(m *debug.Module) *moduleInfo {
+	if m == nil {
+		return nil
+	}
+	return &moduleInfo{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+		Replace: toModuleInfo(m.Replace),
+	}
+}
+
 func getPluginRuntimeVersion() string {
-	buildInfo, ok := debug.ReadBuildInfo()
+	buildInfo, ok := readBuildInfo()
 	if !ok {
 		panic("Can't read BuildInfo")
 	}
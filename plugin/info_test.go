@@ -4,12 +4,17 @@
 package plugin
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"runtime"
+	"runtime/debug"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/buildinfo"
 )
 
 func TestInfo(t *testing.T) {
@@ -70,3 +75,118 @@ func TestInfo(t *testing.T) {
 	assert.Equal(expectedInfo.BinaryArch, gotInfo.BinaryArch)
 	assert.Empty(gotInfo.PluginRuntimeVersion, "Should be empty since unit tests doesn't have the self (tanzu-plugin-runtime) module dependency")
 }
+
+func TestInfoBuildInfo(t *testing.T) {
+	readBuildInfoOrig := readBuildInfo
+	defer func() { readBuildInfo = readBuildInfoOrig }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			GoVersion: "go1.21.5",
+			Main:      debug.Module{Path: "github.com/example/cluster-plugin", Version: "(devel)"},
+			Deps: []*debug.Module{
+				{Path: "github.com/spf13/cobra", Version: "v1.8.0", Sum: "h1:7aJaZx1B85qltLMc546zn58BxxfZdR/W22ej9CFoEf0="},
+				{Path: PluginRuntimeModulePath, Version: "v1.3.0", Replace: &debug.Module{Path: "../tanzu-plugin-runtime", Version: ""}},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "GOOS", Value: "linux"},
+				{Key: "vcs", Value: "git"},
+				{Key: "vcs.revision", Value: "cafecafe"},
+				{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+	buildinfo.Date, buildinfo.SHA, buildinfo.Version = "2024-01-02", "cafecafe", "v1.2.3"
+	defer func() { buildinfo.Date, buildinfo.SHA, buildinfo.Version = "", "", "" }()
+
+	descriptor := PluginDescriptor{
+		Name:        "cluster",
+		Description: "Cluster operations",
+		Version:     "v1.2.3",
+		Group:       ManageCmdGroup,
+	}
+
+	var stdout bytes.Buffer
+	infoCmd := newInfoCmd(&descriptor)
+	infoCmd.SetOut(&stdout)
+	require.NoError(t, infoCmd.Execute())
+
+	gotInfo := &pluginInfo{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), gotInfo))
+	assert.Equal(t, "v1.3.0", gotInfo.PluginRuntimeVersion)
+	assert.Equal(t, &pluginBuildInfo{
+		Date:       "2024-01-02",
+		SHA:        "cafecafe",
+		Version:    "v1.2.3",
+		GoVersion:  "go1.21.5",
+		OS:         runtime.GOOS,
+		MainModule: &moduleInfo{Path: "github.com/example/cluster-plugin", Version: "(devel)"},
+		VCS:        &vcsInfo{System: "git", Revision: "cafecafe", Time: "2024-01-02T03:04:05Z", Modified: true},
+		Dependencies: []*moduleInfo{
+			{Path: "github.com/spf13/cobra", Version: "v1.8.0", Sum: "h1:7aJaZx1B85qltLMc546zn58BxxfZdR/W22ej9CFoEf0="},
+			{Path: PluginRuntimeModulePath, Version: "v1.3.0", Replace: &moduleInfo{Path: "../tanzu-plugin-runtime"}},
+		},
+	}, gotInfo.BuildInfo)
+
+	// CycloneDX SBOM
+	stdout.Reset()
+	infoCmd = newInfoCmd(&descriptor)
+	infoCmd.SetOut(&stdout)
+	infoCmd.SetArgs([]string{"--sbom", SBOMFormatCycloneDX})
+	require.NoError(t, infoCmd.Execute())
+
+	cdx := &cycloneDXSBOM{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), cdx))
+	assert.Equal(t, "CycloneDX", cdx.BOMFormat)
+	assert.Equal(t, "1.5", cdx.SpecVersion)
+	assert.Contains(t, cdx.SerialNumber, "urn:uuid:")
+	assert.Equal(t, cycloneDXComponent{Type: "application", BOMRef: "plugin:cluster", Name: "cluster", Version: "v1.2.3", PURL: "pkg:golang/github.com/example/cluster-plugin"}, cdx.Metadata.Component)
+	assert.Equal(t, []cycloneDXComponent{
+		{
+			Type: "library", BOMRef: "pkg:golang/github.com/spf13/cobra@v1.8.0", Name: "github.com/spf13/cobra", Version: "v1.8.0",
+			PURL:       "pkg:golang/github.com/spf13/cobra@v1.8.0",
+			Properties: []cycloneDXProperty{{Name: "go.sum:h1", Value: "h1:7aJaZx1B85qltLMc546zn58BxxfZdR/W22ej9CFoEf0="}},
+		},
+		{
+			Type: "library", BOMRef: "pkg:golang/" + PluginRuntimeModulePath + "@v1.3.0", Name: PluginRuntimeModulePath, Version: "v1.3.0",
+			PURL: "pkg:golang/" + PluginRuntimeModulePath + "@v1.3.0",
+		},
+	}, cdx.Components)
+	assert.Equal(t, []cycloneDXDependency{{Ref: "plugin:cluster", DependsOn: []string{
+		"pkg:golang/github.com/spf13/cobra@v1.8.0",
+		"pkg:golang/" + PluginRuntimeModulePath + "@v1.3.0",
+	}}}, cdx.Dependencies)
+
+	// SPDX SBOM
+	stdout.Reset()
+	infoCmd = newInfoCmd(&descriptor)
+	infoCmd.SetOut(&stdout)
+	infoCmd.SetArgs([]string{"--sbom", SBOMFormatSPDX})
+	require.NoError(t, infoCmd.Execute())
+
+	spdx := &spdxSBOM{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), spdx))
+	assert.Equal(t, "SPDX-2.3", spdx.SPDXVersion)
+	assert.Equal(t, []string{"Tool: tanzu-plugin-runtime-v1.3.0"}, spdx.CreationInfo.Creators)
+	require.Len(t, spdx.Packages, 3)
+	assert.Equal(t, "SPDXRef-Package-plugin", spdx.Packages[0].SPDXID)
+	assert.Equal(t, spdxPackage{
+		Name:             "github.com/spf13/cobra",
+		SPDXID:           "SPDXRef-Package-1",
+		VersionInfo:      "v1.8.0",
+		DownloadLocation: "NOASSERTION",
+		ExternalRefs:     []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:golang/github.com/spf13/cobra@v1.8.0"}},
+	}, spdx.Packages[1])
+	assert.Equal(t, []spdxRelationship{
+		{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Package-plugin"},
+		{SPDXElementID: "SPDXRef-Package-plugin", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-1"},
+		{SPDXElementID: "SPDXRef-Package-plugin", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-2"},
+	}, spdx.Relationships)
+
+	// Unsupported SBOM format
+	infoCmd = newInfoCmd(&descriptor)
+	infoCmd.SetOut(&stdout)
+	infoCmd.SetErr(&stdout)
+	infoCmd.SetArgs([]string{"--sbom", "swid"})
+	assert.EqualError(t, infoCmd.Execute(), `unsupported SBOM format "swid", supported formats are cyclonedx and spdx`)
+}
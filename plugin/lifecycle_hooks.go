@@ -0,0 +1,209 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+const (
+	// PreUninstallHookName is the name of the hidden command running the PreUninstallHook of the plugin
+	PreUninstallHookName = "pre-uninstall"
+	// PostUpgradeHookName is the name of the hidden command running the PostUpgradeHook of the plugin
+	PostUpgradeHookName = "post-upgrade"
+	// ContextChangedHookName is the name of the hidden command running the ContextChangedHook of the plugin
+	ContextChangedHookName = "context-changed"
+
+	// DefaultHookTimeout is the maximum duration of a lifecycle hook unless specified with the --timeout flag
+	DefaultHookTimeout = 5 * time.Minute
+
+	// ErrCodeHookTimeout is the code of the error returned when a lifecycle hook does not complete in time
+	ErrCodeHookTimeout = "HOOK_TIMEOUT"
+)
+
+// hookStopGracePeriod is the duration the runtime waits for a lifecycle hook to return once its context is
+// cancelled on timeout, before reporting the timeout
+var hookStopGracePeriod = 5 * time.Second
+
+// HookStatus is the status of the run of a lifecycle hook
+type HookStatus string
+
+const (
+	// HookStatusSucceeded indicates the hook completed successfully
+	HookStatusSucceeded HookStatus = "succeeded"
+	// HookStatusFailed indicates the hook returned an error
+	HookStatusFailed HookStatus = "failed"
+	// HookStatusTimedOut indicates the hook did not complete in time
+	HookStatusTimedOut HookStatus = "timed-out"
+	// HookStatusSkipped indicates the plugin does not define the hook
+	HookStatusSkipped HookStatus = "skipped"
+)
+
+// HookInput is the input of a lifecycle hook, provided by the CLI as JSON on the stdin of the hook command
+type HookInput struct {
+	// PreviousVersion is the version of the plugin before the upgrade, for the post-upgrade hook
+	PreviousVersion string `json:"previousVersion,omitempty" yaml:"previousVersion,omitempty"`
+	// Context is the name of the context which became active, for the context-changed hook
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	// ContextType is the type of the context which became active, for the context-changed hook
+	ContextType types.ContextType `json:"contextType,omitempty" yaml:"contextType,omitempty"`
+	// PreviousContext is the name of the context previously active, for the context-changed hook
+	PreviousContext string `json:"previousContext,omitempty" yaml:"previousContext,omitempty"`
+}
+
+// HookResult is the result of a lifecycle hook, written as JSON on the stdout of the hook command
+type HookResult struct {
+	// Hook is the name of the hook e.g. post-upgrade
+	Hook string `json:"hook" yaml:"hook"`
+	// Status of the run of the hook, set by the runtime
+	Status HookStatus `json:"status" yaml:"status"`
+	// Message returned by the hook to be reported to the user
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+	// Error is the error of the hook, empty if the hook succeeded
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+	// Data is the structured output specific to the hook
+	Data map[string]interface{} `json:"data,omitempty" yaml:"data,omitempty"`
+}
+
+func newPreUninstallCmd(desc *PluginDescriptor) *cobra.Command {
+	return newLifecycleHookCmd(desc, PreUninstallHookName, "Run pre uninstall cleanup for a plugin", func() LifecycleHook {
+		return desc.PreUninstallHook
+	})
+}
+
+func newPostUpgradeCmd(desc *PluginDescriptor) *cobra.Command {
+	return newLifecycleHookCmd(desc, PostUpgradeHookName, "Run post upgrade configuration for a plugin", func() LifecycleHook {
+		return desc.PostUpgradeHook
+	})
+}
+
+func newContextChangedCmd(desc *PluginDescriptor) *cobra.Command {
+	return newLifecycleHookCmd(desc, ContextChangedHookName, "Update a plugin when the active context changes", func() LifecycleHook {
+		return desc.ContextChangedHook
+	})
+}
+
+// newLifecycleHookCmd returns the hidden command running the lifecycle hook. The command reads the HookInput
+// from stdin and writes the HookResult to stdout, including when the hook fails or times out.
+func newLifecycleHookCmd(desc *PluginDescriptor, name, short string, getHook func() LifecycleHook) *cobra.Command {
+	var timeout time.Duration
+	cmd := &cobra.Command{
+		Use:          name,
+		Short:        short,
+		Long:         short + ". The input of the hook is read as JSON from stdin and its result is written as JSON to stdout.",
+		Hidden:       true,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		Annotations:  map[string]string{cliCommandAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := readHookInput(cmd.InOrStdin())
+			if err != nil {
+				return pluginerrors.NewUsageError(fmt.Sprintf("invalid input of the %s hook", name), pluginerrors.WithCause(err))
+			}
+			result, err := runLifecycleHook(cmd.Context(), desc, name, getHook(), input, timeout)
+			b, merr := json.Marshal(result)
+			if merr != nil {
+				return merr
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			return err
+		},
+	}
+	cmd.Flags().DurationVar(&timeout, "timeout", DefaultHookTimeout, "Maximum duration of the hook")
+
+	return cmd
+}
+
+// readHookInput reads the JSON input of the hook, an empty input if stdin is empty or a terminal
+func readHookInput(r io.Reader) (*HookInput, error) {
+	input := &HookInput{}
+	if f, ok := r.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			return input, nil
+		}
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return input, nil
+	}
+	if err := json.Unmarshal(b, input); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+// runLifecycleHook runs the hook with the timeout and returns its result. The hook is skipped if not defined.
+// A panic of the hook is recovered as a failure of the hook, after writing a crash report.
+// The context of the hook is cancelled on timeout and the hook is given hookStopGracePeriod to return before
+// the timeout is reported. A hook ignoring its context keeps running in the background until the plugin exits.
+func runLifecycleHook(ctx context.Context, desc *PluginDescriptor, name string, hook LifecycleHook, input *HookInput, timeout time.Duration) (*HookResult, error) {
+	if hook == nil {
+		return &HookResult{Hook: name, Status: HookStatusSkipped}, nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type hookOutcome struct {
+		result *HookResult
+		err    error
+	}
+	done := make(chan hookOutcome, 1)
+	go func() {
+		// The hook runs in its own goroutine, so its panics are not recovered by Plugin.Execute
+		defer func() {
+			if r := recover(); r != nil {
+				done <- hookOutcome{err: newCrashError(desc, r, debug.Stack())}
+			}
+		}()
+		result, err := hook(ctx, input)
+		done <- hookOutcome{result: result, err: err}
+	}()
+
+	select {
+	case outcome := <-done:
+		result := outcome.result
+		if result == nil {
+			result = &HookResult{}
+		}
+		result.Hook = name
+		if outcome.err != nil {
+			result.Status = HookStatusFailed
+			result.Error = outcome.err.Error()
+			return result, outcome.err
+		}
+		result.Status = HookStatusSucceeded
+		return result, nil
+	case <-ctx.Done():
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(hookStopGracePeriod):
+		}
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &HookResult{Hook: name, Status: HookStatusFailed, Error: ctx.Err().Error()}, ctx.Err()
+		}
+		err := pluginerrors.NewUnavailableError(fmt.Sprintf("the %s hook did not complete within %s", name, timeout),
+			pluginerrors.WithCode(ErrCodeHookTimeout))
+		return &HookResult{Hook: name, Status: HookStatusTimedOut, Error: err.Error()}, err
+	}
+}
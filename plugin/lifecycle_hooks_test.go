@@ -0,0 +1,161 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+func TestLifecycleHooks(t *testing.T) {
+	tests := []struct {
+		name   string
+		desc   PluginDescriptor
+		args   []string
+		input  string
+		result HookResult
+		err    string
+		kind   pluginerrors.Kind
+	}{
+		{
+			name:   "hook not defined",
+			args:   []string{PreUninstallHookName},
+			result: HookResult{Hook: PreUninstallHookName, Status: HookStatusSkipped},
+		},
+		{
+			name: "post-upgrade receives the previous version",
+			desc: PluginDescriptor{PostUpgradeHook: func(ctx context.Context, input *HookInput) (*HookResult, error) {
+				return &HookResult{Message: "upgraded from " + input.PreviousVersion}, nil
+			}},
+			args:   []string{PostUpgradeHookName},
+			input:  `{"previousVersion": "v1.0.0"}`,
+			result: HookResult{Hook: PostUpgradeHookName, Status: HookStatusSucceeded, Message: "upgraded from v1.0.0"},
+		},
+		{
+			name: "context-changed receives the context",
+			desc: PluginDescriptor{ContextChangedHook: func(ctx context.Context, input *HookInput) (*HookResult, error) {
+				return &HookResult{Data: map[string]interface{}{"context": input.Context, "contextType": string(input.ContextType)}}, nil
+			}},
+			args:  []string{ContextChangedHookName},
+			input: `{"context": "staging", "contextType": "kubernetes", "previousContext": "prod"}`,
+			result: HookResult{
+				Hook:   ContextChangedHookName,
+				Status: HookStatusSucceeded,
+				Data:   map[string]interface{}{"context": "staging", "contextType": string(types.ContextTypeK8s)},
+			},
+		},
+		{
+			name: "hook fails",
+			desc: PluginDescriptor{PreUninstallHook: func(ctx context.Context, input *HookInput) (*HookResult, error) {
+				return nil, errors.New("resources still in use")
+			}},
+			args:   []string{PreUninstallHookName},
+			result: HookResult{Hook: PreUninstallHookName, Status: HookStatusFailed, Error: "resources still in use"},
+			err:    "resources still in use",
+			kind:   pluginerrors.KindInternal,
+		},
+		{
+			name: "hook times out",
+			desc: PluginDescriptor{PreUninstallHook: func(ctx context.Context, input *HookInput) (*HookResult, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}},
+			args:   []string{PreUninstallHookName, "--timeout", "10ms"},
+			result: HookResult{Hook: PreUninstallHookName, Status: HookStatusTimedOut, Error: "the pre-uninstall hook did not complete within 10ms"},
+			err:    "the pre-uninstall hook did not complete within 10ms",
+			kind:   pluginerrors.KindUnavailable,
+		},
+		{
+			name: "hook panics",
+			desc: PluginDescriptor{ContextChangedHook: func(ctx context.Context, input *HookInput) (*HookResult, error) {
+				panic("kubeconfig not found")
+			}},
+			args:   []string{ContextChangedHookName},
+			result: HookResult{Hook: ContextChangedHookName, Status: HookStatusFailed, Error: "the test-plugin plugin crashed: kubeconfig not found"},
+			err:    "the test-plugin plugin crashed: kubeconfig not found",
+			kind:   pluginerrors.KindInternal,
+		},
+		{
+			name:  "invalid input",
+			args:  []string{PostUpgradeHookName},
+			input: `{"previousVersion":`,
+			err:   "invalid input of the post-upgrade hook: unexpected end of JSON input",
+			kind:  pluginerrors.KindUsage,
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			t.Setenv(config.EnvStateDirKey, t.TempDir())
+			desc := spec.desc
+			desc.Name = "test-plugin"
+			desc.Target = types.TargetK8s
+			desc.Description = "Description of the plugin"
+			desc.Version = "v1.2.3"
+			desc.Group = ManageCmdGroup
+			p, err := NewPlugin(&desc)
+			require.NoError(t, err)
+
+			var stdout, stderr bytes.Buffer
+			p.Cmd.SetIn(strings.NewReader(spec.input))
+			p.Cmd.SetOut(&stdout)
+			p.Cmd.SetErr(&stderr)
+			p.Cmd.SetArgs(spec.args)
+			err = p.Execute()
+			if spec.err != "" {
+				assert.EqualError(t, err, spec.err)
+				assert.Equal(t, spec.kind, pluginerrors.KindOf(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			if spec.result.Hook == "" {
+				assert.Empty(t, stdout.String())
+				return
+			}
+			var result HookResult
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+			assert.Equal(t, spec.result, result)
+		})
+	}
+}
+
+func TestLifecycleHookTimeoutCancelsContext(t *testing.T) {
+	desc := &PluginDescriptor{Name: "test-plugin"}
+
+	// The hook returning once its context is cancelled completes before the timeout is reported
+	stopped := false
+	result, err := runLifecycleHook(context.Background(), desc, PreUninstallHookName, func(ctx context.Context, input *HookInput) (*HookResult, error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		stopped = true
+		return nil, ctx.Err()
+	}, &HookInput{}, 10*time.Millisecond)
+	assert.EqualError(t, err, "the pre-uninstall hook did not complete within 10ms")
+	assert.Equal(t, HookStatusTimedOut, result.Status)
+	assert.True(t, stopped)
+
+	// The timeout of the hook ignoring its context is reported after the grace period
+	gracePeriod := hookStopGracePeriod
+	hookStopGracePeriod = 10 * time.Millisecond
+	defer func() { hookStopGracePeriod = gracePeriod }()
+	release := make(chan struct{})
+	defer close(release)
+	result, err = runLifecycleHook(context.Background(), desc, PreUninstallHookName, func(ctx context.Context, input *HookInput) (*HookResult, error) {
+		<-release
+		return nil, nil
+	}, &HookInput{}, 10*time.Millisecond)
+	assert.EqualError(t, err, "the pre-uninstall hook did not complete within 10ms")
+	assert.Equal(t, HookStatusTimedOut, result.Status)
+}
@@ -5,18 +5,30 @@ package plugin
 
 import (
 	"fmt"
+	"runtime/debug"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"go.uber.org/multierr"
 	"golang.org/x/mod/semver"
 
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
 )
 
 // Plugin is a Tanzu CLI plugin.
 type Plugin struct {
 	Cmd *cobra.Command
+
+	descriptor    *PluginDescriptor
+	telemetrySink TelemetrySink
+	// wrappedArgs are the commands whose validators of the arguments return Usage errors
+	wrappedArgs map[*cobra.Command]bool
+	// executedCmd is the command being executed, once its arguments are validated
+	executedCmd *cobra.Command
 }
 
 // NewPlugin creates an instance of Plugin.
@@ -26,12 +38,31 @@ func NewPlugin(descriptor *PluginDescriptor) (*Plugin, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid PluginDescriptor specified")
 	}
+	err = config.RegisterFeatureFlags(descriptor.Name, getFeatureFlagSpecs(descriptor)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to register the feature flags")
+	}
+	config.SetConfigSnapshotSource(descriptor.Name)
 	p := &Plugin{
-		Cmd: newRootCmd(descriptor),
+		Cmd:        newRootCmd(descriptor),
+		descriptor: descriptor,
 	}
 	p.Cmd.AddCommand(lintCmd)
 	p.Cmd.AddCommand(genDocsCmd)
 	p.Cmd.AddCommand(newPostInstallCmd(descriptor))
+	p.Cmd.AddCommand(newPreUninstallCmd(descriptor))
+	p.Cmd.AddCommand(newPostUpgradeCmd(descriptor))
+	p.Cmd.AddCommand(newContextChangedCmd(descriptor))
+	p.Cmd.AddCommand(newCommandsCmd(descriptor))
+	p.Cmd.AddCommand(newGenerateManifestCmd(descriptor))
+
+	// The commands of the runtime never require an active context
+	for _, c := range p.Cmd.Commands() {
+		if c.Annotations == nil {
+			c.Annotations = make(map[string]string)
+		}
+		c.Annotations[ContextRequiredAnnotation] = "false"
+	}
 	return p, nil
 }
 
@@ -41,9 +72,69 @@ func (p *Plugin) AddCommands(commands ...*cobra.Command) {
 }
 
 // Execute executes the plugin.
+// The error of the command is returned as a typed error of the plugin/errors package and
+// rendered to stderr, as JSON if the command is invoked with `--output json`. The errors which
+// are not typed are returned as Internal errors and the invalid arguments or flags as Usage errors,
+// followed by the usage of the command unless the command silenced it.
+// The caller should exit with the exit code of the error e.g. `os.Exit(errors.ExitCode(err))`, see docs/errors.md.
+// To do so, the Args validator of every command is replaced with a validator calling the original one
+// (cobra.ArbitraryArgs for the sub-commands without validator) which also validates the required flags and the
+// flag groups, and the error and usage output of cobra is silenced while the command is executed.
+// The panics of the commands are recovered and returned as Internal errors, after writing a crash
+// report in the crash reports directory of the tanzu state directory.
+// The plugins opting in with PluginDescriptor.ContextOverride accept the `--context` flag and the TANZU_CONTEXT
+// environment variable, an unknown context is returned as a Usage error.
+// A telemetry event is recorded for the invocation if enabled with EnableTelemetry.
 func (p *Plugin) Execute() error {
 	propagateTargetAnnotation(p.Cmd)
-	return p.Cmd.Execute()
+	if p.descriptor != nil && p.descriptor.ContextOverride {
+		applyContextOverrideEnv()
+		addContextOverrideFlag(p.Cmd)
+	}
+	p.wrapArgsValidators(p.Cmd)
+
+	// The errors and the usage are rendered once the error is typed, instead of by cobra
+	silenceErrors, silenceUsage := p.Cmd.SilenceErrors, p.Cmd.SilenceUsage
+	p.Cmd.SilenceErrors, p.Cmd.SilenceUsage = true, true
+	defer func() {
+		p.Cmd.SilenceErrors, p.Cmd.SilenceUsage = silenceErrors, silenceUsage
+	}()
+
+	start := time.Now()
+	cmd, err := p.executeC()
+	if err == nil {
+		p.recordTelemetryEvent(cmd, nil, start)
+		return nil
+	}
+	pluginErr := toPluginError(cmd, err)
+	p.recordTelemetryEvent(cmd, pluginErr, start)
+	// The errors and the usage are not rendered if the plugin or the command silenced them
+	if !silenceErrors && (cmd == p.Cmd || !cmd.SilenceErrors) {
+		_ = pluginerrors.Render(cmd.ErrOrStderr(), pluginErr, getOutputFormat(cmd))
+	}
+	if !silenceUsage && (cmd == p.Cmd || !cmd.SilenceUsage) {
+		printUsage(cmd, pluginErr)
+	}
+	return pluginErr
+}
+
+// executeC executes the root command, recovering the panics of the commands as crash errors of the command
+// being executed
+func (p *Plugin) executeC() (cmd *cobra.Command, err error) {
+	p.executedCmd = nil
+	defer func() {
+		if r := recover(); r != nil {
+			desc := p.descriptor
+			if desc == nil {
+				desc = &PluginDescriptor{Name: p.Cmd.Name()}
+			}
+			cmd, err = p.Cmd, newCrashError(desc, r, debug.Stack())
+			if p.executedCmd != nil {
+				cmd = p.executedCmd
+			}
+		}
+	}()
+	return p.Cmd.ExecuteC()
 }
 
 // propagateTargetAnnotation propagates the target annotation from parent command to all its children.
@@ -102,5 +193,30 @@ func ValidatePlugin(p *PluginDescriptor) (err error) {
 	if p.Group == "" {
 		err = multierr.Append(err, fmt.Errorf("plugin %q: group cannot be empty", p.Name))
 	}
+	for i := range p.FeatureFlags {
+		if flagErr := p.FeatureFlags[i].Validate(); flagErr != nil {
+			err = multierr.Append(err, fmt.Errorf("plugin %q: %v", p.Name, flagErr))
+		}
+	}
 	return
 }
+
+// getFeatureFlagSpecs returns the feature flags declared by the plugin including the
+// default feature flags not explicitly declared
+func getFeatureFlagSpecs(p *PluginDescriptor) []types.FeatureFlagSpec {
+	specs := append([]types.FeatureFlagSpec{}, p.FeatureFlags...)
+	declared := make(map[string]bool)
+	for _, spec := range p.FeatureFlags {
+		declared[spec.Name] = true
+	}
+	for name, value := range p.DefaultFeatureFlags {
+		if !declared[name] {
+			specs = append(specs, types.FeatureFlagSpec{
+				Name:    name,
+				Type:    types.FeatureFlagTypeBool,
+				Default: strconv.FormatBool(value),
+			})
+		}
+	}
+	return specs
+}
@@ -0,0 +1,255 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+// maxSyncOutputLineSize is the max size of a line of the output of the plugin sync command of the CLI
+const maxSyncOutputLineSize = 1024 * 1024
+
+// PluginSyncStatus is the status of a plugin after the sync
+type PluginSyncStatus string
+
+const (
+	// PluginSyncStatusInstalled indicates the plugin was installed
+	PluginSyncStatusInstalled PluginSyncStatus = "installed"
+	// PluginSyncStatusSkipped indicates the plugin was not installed e.g. already installed
+	PluginSyncStatusSkipped PluginSyncStatus = "skipped"
+	// PluginSyncStatusFailed indicates the installation of the plugin failed
+	PluginSyncStatusFailed PluginSyncStatus = "failed"
+)
+
+// PluginSyncEventType is the type of a PluginSyncEvent
+type PluginSyncEventType string
+
+const (
+	// PluginSyncEventPlugin is the event reporting the status of a plugin
+	PluginSyncEventPlugin PluginSyncEventType = "plugin"
+	// PluginSyncEventMessage is the event reporting a message of the sync e.g. a line of output of the CLI
+	PluginSyncEventMessage PluginSyncEventType = "message"
+)
+
+// SyncedPlugin describes the outcome of the sync of a plugin
+type SyncedPlugin struct {
+	// Name of the plugin
+	Name string `json:"name" yaml:"name"`
+	// Target of the plugin
+	Target types.Target `json:"target,omitempty" yaml:"target,omitempty"`
+	// Version of the plugin
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	// Status of the plugin after the sync
+	Status PluginSyncStatus `json:"status" yaml:"status"`
+	// Reason the plugin was skipped or failed
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// PluginSyncEvent is a progress event of the sync of the plugins
+type PluginSyncEvent struct {
+	// Type of the event
+	Type PluginSyncEventType `json:"type" yaml:"type"`
+	// Plugin is the plugin whose status is reported, for the events of type plugin
+	Plugin *SyncedPlugin `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	// Message of the event, for the events of type message
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// PluginSyncResult is the result of the sync of the plugins of a context type
+type PluginSyncResult struct {
+	// ContextType is the type of the active context the plugins were synced for
+	ContextType types.ContextType `json:"contextType" yaml:"contextType"`
+	// Installed are the plugins installed
+	Installed []SyncedPlugin `json:"installed,omitempty" yaml:"installed,omitempty"`
+	// Skipped are the plugins not installed, with the reason
+	Skipped []SyncedPlugin `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	// Failed are the plugins which failed to install, with the reason
+	Failed []SyncedPlugin `json:"failed,omitempty" yaml:"failed,omitempty"`
+	// Output is the output of the sync which is not reported as plugin events
+	Output string `json:"output,omitempty" yaml:"output,omitempty"`
+	// OutputOnly indicates the plugins installed, skipped and failed are not reported, only the Output of the
+	// sync e.g. when the plugins are synced by invoking the `plugin sync` command of the CLI
+	OutputOnly bool `json:"outputOnly,omitempty" yaml:"outputOnly,omitempty"`
+}
+
+// PluginSyncProgressFunc receives the progress events of the sync of the plugins
+type PluginSyncProgressFunc func(event *PluginSyncEvent)
+
+// PluginSyncer installs the plugins required by the active context of a context type
+type PluginSyncer interface {
+	// SyncPlugins installs the plugins required by the active context of the contextType, reporting the
+	// progress to the progress func. The sync stops when the context is done.
+	SyncPlugins(ctx context.Context, contextType types.ContextType, progress PluginSyncProgressFunc) (*PluginSyncResult, error)
+}
+
+// pluginSyncer is the PluginSyncer registered with RegisterPluginSyncer
+var pluginSyncer = struct {
+	sync.RWMutex
+	syncer PluginSyncer
+}{}
+
+// RegisterPluginSyncer registers the PluginSyncer used by SyncPlugins, typically by the core CLI to sync the
+// plugins in-process. The registered PluginSyncer is removed with a nil syncer.
+func RegisterPluginSyncer(syncer PluginSyncer) {
+	pluginSyncer.Lock()
+	defer pluginSyncer.Unlock()
+	pluginSyncer.syncer = syncer
+}
+
+// getPluginSyncer returns the registered PluginSyncer, or the PluginSyncer invoking the CLI specified with TANZU_BIN
+func getPluginSyncer() (PluginSyncer, error) {
+	pluginSyncer.RLock()
+	syncer := pluginSyncer.syncer
+	pluginSyncer.RUnlock()
+	if syncer != nil {
+		return syncer, nil
+	}
+	cliPath := os.Getenv("TANZU_BIN")
+	if cliPath == "" {
+		return nil, errors.New("no plugin syncer is registered and the environment variable TANZU_BIN is not set")
+	}
+	return &subprocessPluginSyncer{cliPath: cliPath}, nil
+}
+
+// syncOptions specifies the options of SyncPlugins
+type syncOptions struct {
+	progress PluginSyncProgressFunc
+}
+
+// SyncOption is a function type that applies optional settings to SyncPlugins
+type SyncOption func(o *syncOptions)
+
+// WithSyncProgress specifies the SyncOption for receiving the progress events of the sync
+func WithSyncProgress(progress PluginSyncProgressFunc) SyncOption {
+	return func(o *syncOptions) {
+		o.progress = progress
+	}
+}
+
+// SyncPlugins installs the plugins required by the active Context of the provided contextType and returns the
+// plugins installed, skipped and failed. The plugins are synced with the PluginSyncer registered with
+// RegisterPluginSyncer, or by invoking `plugin sync` of the CLI specified with the TANZU_BIN environment variable.
+//
+// The plugins installed, skipped and failed are only reported by a registered PluginSyncer. When the CLI is
+// invoked, the result is OutputOnly: it only has the Output of the sync and the progress func only receives
+// message events.
+//
+// Note: This API is considered EXPERIMENTAL. Both the function's signature and
+// implementation are subjected to change/removal if an alternative means to
+// provide equivalent functionality can be introduced.
+//
+// Example:
+//
+//	result, err := SyncPlugins(ctx, types.ContextTypeK8s, WithSyncProgress(func(e *PluginSyncEvent) {
+//		if e.Type == PluginSyncEventPlugin {
+//			log.Infof("%s: %s", e.Plugin.Name, e.Plugin.Status)
+//		}
+//	}))
+func SyncPlugins(ctx context.Context, contextType types.ContextType, opts ...SyncOption) (*PluginSyncResult, error) {
+	options := &syncOptions{progress: func(*PluginSyncEvent) {}}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.progress == nil {
+		options.progress = func(*PluginSyncEvent) {}
+	}
+
+	syncer, err := getPluginSyncer()
+	if err != nil {
+		return nil, err
+	}
+	return syncer.SyncPlugins(ctx, contextType, options.progress)
+}
+
+// subprocessPluginSyncer syncs the plugins by invoking the `plugin sync` command of the CLI
+type subprocessPluginSyncer struct {
+	cliPath string
+}
+
+// SyncPlugins invokes the `plugin sync` command of the CLI, or the alternate command returned by its `_custom_command`.
+// The CLI does not report the status of the plugins in a structured way, so the lines of its output are reported as
+// message events and the result is OutputOnly.
+// The output is read until the command exits, even after a line exceeding maxSyncOutputLineSize which fails the sync.
+func (s *subprocessPluginSyncer) SyncPlugins(ctx context.Context, contextType types.ContextType, progress PluginSyncProgressFunc) (*PluginSyncResult, error) {
+	args := getSyncPluginsArgs(ctx, s.cliPath, contextType)
+
+	command := exec.CommandContext(ctx, s.cliPath, args...)
+	stdout, err := command.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sync the plugins")
+	}
+	stderr, err := command.StderrPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sync the plugins")
+	}
+	if err := command.Start(); err != nil {
+		return nil, errors.Wrap(err, "failed to sync the plugins")
+	}
+
+	// The pipes are closed when the context is done as the children of the CLI may keep them open
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = stdout.Close()
+			_ = stderr.Close()
+		case <-done:
+		}
+	}()
+
+	result := &PluginSyncResult{ContextType: contextType, OutputOnly: true}
+	var output strings.Builder
+	var scanErr error
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, r := range []io.Reader{stdout, stderr} {
+		wg.Add(1)
+		go func(r io.Reader) {
+			defer wg.Done()
+			scanner := bufio.NewScanner(r)
+			scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxSyncOutputLineSize)
+			for scanner.Scan() {
+				event := &PluginSyncEvent{Type: PluginSyncEventMessage, Message: scanner.Text()}
+				mu.Lock()
+				output.WriteString(event.Message + "\n")
+				progress(event)
+				mu.Unlock()
+			}
+			if err := scanner.Err(); err != nil {
+				mu.Lock()
+				if scanErr == nil {
+					scanErr = err
+				}
+				mu.Unlock()
+				// The rest of the output is discarded so the command does not block writing to the pipe
+				_, _ = io.Copy(io.Discard, r)
+			}
+		}(r)
+	}
+	wg.Wait()
+	err = command.Wait()
+	result.Output = output.String()
+
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if err != nil {
+		return result, errors.Wrap(err, "failed to sync the plugins")
+	}
+	if scanErr != nil {
+		return result, errors.Wrap(scanErr, "failed to read the output of the plugin sync")
+	}
+	return result, nil
+}
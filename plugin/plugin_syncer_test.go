@@ -0,0 +1,135 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
+
+const fakeSyncCLIScript = `#!/bin/bash
+# Fake tanzu core binary syncing the plugins
+
+case "$1" in
+    _custom_command) exit 1;;
+    plugin)
+        echo "Installing plugin 'cluster:v1.0.0' with target 'kubernetes'"
+        >&2 echo "unable to install plugin 'apps': not found"
+        if [ -n "$FAKE_SYNC_LINE_SIZE" ]; then
+            head -c "$FAKE_SYNC_LINE_SIZE" /dev/zero | tr '\0' x
+            echo
+            echo "Plugins installed"
+        fi
+        if [ -n "$FAKE_SYNC_SLEEP" ]; then
+            sleep "$FAKE_SYNC_SLEEP"
+        fi
+        exit "${FAKE_SYNC_EXIT:-0}"
+        ;;
+    *) exit 1;;
+esac
+`
+
+func setupFakeSyncCLI(t *testing.T) {
+	cliPath := filepath.Join(t.TempDir(), "tanzu")
+	require.NoError(t, os.WriteFile(cliPath, []byte(fakeSyncCLIScript), 0o755))
+	t.Setenv("TANZU_BIN", cliPath)
+}
+
+func TestSyncPluginsWithSubprocess(t *testing.T) {
+	setupFakeSyncCLI(t)
+
+	var events []*PluginSyncEvent
+	result, err := SyncPlugins(context.Background(), types.ContextTypeK8s, WithSyncProgress(func(e *PluginSyncEvent) {
+		events = append(events, e)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, types.ContextTypeK8s, result.ContextType)
+	// The plugins are not reported by the CLI, only its output
+	assert.True(t, result.OutputOnly)
+	assert.Empty(t, result.Installed)
+	assert.Empty(t, result.Skipped)
+	assert.Empty(t, result.Failed)
+	assert.Contains(t, result.Output, "Installing plugin 'cluster:v1.0.0' with target 'kubernetes'\n")
+	assert.Contains(t, result.Output, "unable to install plugin 'apps': not found\n")
+	require.Len(t, events, 2)
+	for _, e := range events {
+		assert.Equal(t, PluginSyncEventMessage, e.Type)
+		assert.Nil(t, e.Plugin)
+	}
+
+	t.Setenv("FAKE_SYNC_EXIT", "1")
+	result, err = SyncPlugins(context.Background(), types.ContextTypeK8s)
+	assert.ErrorContains(t, err, "failed to sync the plugins")
+	require.NotNil(t, result)
+	assert.True(t, result.OutputOnly)
+	assert.Contains(t, result.Output, "unable to install plugin 'apps': not found\n")
+}
+
+func TestSyncPluginsWithLongOutputLines(t *testing.T) {
+	setupFakeSyncCLI(t)
+
+	// The lines longer than the default buffer of the scanner are read
+	t.Setenv("FAKE_SYNC_LINE_SIZE", "100000")
+	result, err := SyncPlugins(context.Background(), types.ContextTypeK8s)
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "Plugins installed\n")
+
+	// The output is drained after a line exceeding the max size, so the command completes
+	t.Setenv("FAKE_SYNC_LINE_SIZE", "2000000")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err = SyncPlugins(ctx, types.ContextTypeK8s)
+	assert.ErrorIs(t, err, bufio.ErrTooLong)
+	assert.ErrorContains(t, err, "failed to read the output of the plugin sync")
+	require.NotNil(t, result)
+	assert.NotContains(t, result.Output, "Plugins installed")
+}
+
+func TestSyncPluginsCancel(t *testing.T) {
+	setupFakeSyncCLI(t)
+	t.Setenv("FAKE_SYNC_SLEEP", "10")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := SyncPlugins(ctx, types.ContextTypeK8s)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// fakePluginSyncer reports the plugins as installed
+type fakePluginSyncer struct{}
+
+func (fakePluginSyncer) SyncPlugins(ctx context.Context, contextType types.ContextType, progress PluginSyncProgressFunc) (*PluginSyncResult, error) {
+	plugin := SyncedPlugin{Name: "cluster", Status: PluginSyncStatusInstalled}
+	progress(&PluginSyncEvent{Type: PluginSyncEventPlugin, Plugin: &plugin})
+	return &PluginSyncResult{ContextType: contextType, Installed: []SyncedPlugin{plugin}}, nil
+}
+
+func TestSyncPluginsWithRegisteredSyncer(t *testing.T) {
+	t.Setenv("TANZU_BIN", "")
+	_, err := SyncPlugins(context.Background(), types.ContextTypeTanzu)
+	assert.EqualError(t, err, "no plugin syncer is registered and the environment variable TANZU_BIN is not set")
+
+	RegisterPluginSyncer(fakePluginSyncer{})
+	defer RegisterPluginSyncer(nil)
+	var events []*PluginSyncEvent
+	result, err := SyncPlugins(context.Background(), types.ContextTypeTanzu, WithSyncProgress(func(e *PluginSyncEvent) {
+		events = append(events, e)
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, types.ContextTypeTanzu, result.ContextType)
+	assert.False(t, result.OutputOnly)
+	assert.Len(t, result.Installed, 1)
+	assert.Len(t, events, 1)
+}
@@ -4,12 +4,18 @@
 package plugin
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
 )
 
 func TestValidatePlugin(t *testing.T) {
@@ -33,6 +39,38 @@ func TestValidatePlugin(t *testing.T) {
 	err = ValidatePlugin(&descriptor)
 	assert.ErrorContains(err, "plugin name cannot be empty")
 	assert.ErrorContains(err, "is not a valid semantic version")
+
+	descriptor.FeatureFlags = []types.FeatureFlagSpec{{Name: "retries", Type: types.FeatureFlagTypeInt, Default: "many"}}
+	err = ValidatePlugin(&descriptor)
+	assert.ErrorContains(err, "invalid default value: feature flag \"retries\": value \"many\" is not a valid int")
+}
+
+func TestNewPluginRegistersFeatureFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	descriptor := PluginDescriptor{
+		Name:        "feature-flags-plugin",
+		Target:      types.TargetGlobal,
+		Description: "Description of the plugin",
+		Version:     "v1.2.3",
+		Group:       "TestGroup",
+		DefaultFeatureFlags: map[string]bool{
+			"dual-stack": true,
+			"retries":    true,
+		},
+		FeatureFlags: []types.FeatureFlagSpec{
+			{Name: "retries", Type: types.FeatureFlagTypeInt, Default: "3", Owner: "team"},
+		},
+	}
+
+	_, err := NewPlugin(&descriptor)
+	assert.NoError(err)
+
+	specs := config.GetRegisteredFeatureFlags("feature-flags-plugin")
+	assert.Equal([]types.FeatureFlagSpec{
+		{Name: "dual-stack", Type: types.FeatureFlagTypeBool, Default: "true"},
+		{Name: "retries", Type: types.FeatureFlagTypeInt, Default: "3", Owner: "team"},
+	}, specs)
 }
 
 func TestNewPlugin(t *testing.T) {
@@ -85,8 +123,9 @@ func TestAddCommands(t *testing.T) {
 	}
 	cmd.AddCommands(subCmd)
 
-	// Plugin gets 6 commands by default (describe, info, version, lint, post-install, generate-docs), ours should make 7.
-	assert.Equal(7, len(cmd.Cmd.Commands()))
+	// Plugin gets 11 commands by default (describe, info, version, lint, post-install, pre-uninstall, post-upgrade,
+	// context-changed, generate-docs, commands, generate-manifest), ours should make 12.
+	assert.Equal(12, len(cmd.Cmd.Commands()))
 }
 
 func TestExecute(t *testing.T) {
@@ -110,3 +149,178 @@ func TestExecute(t *testing.T) {
 
 	assert.Nil(cmd.Execute())
 }
+
+func TestExecuteErrors(t *testing.T) {
+	descriptor := PluginDescriptor{
+		Name:        "test-plugin",
+		Target:      types.TargetGlobal,
+		Description: "Description of the plugin",
+		Version:     "v1.2.3",
+		Group:       "TestGroup",
+	}
+	p, err := NewPlugin(&descriptor)
+	require.NoError(t, err)
+
+	var output string
+	getCmd := &cobra.Command{
+		Use:  "get",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pluginerrors.NewNotFoundError(fmt.Sprintf("resource %q not found", args[0]),
+				pluginerrors.WithHint("Run 'test-plugin list' to list the resources."))
+		},
+	}
+	getCmd.Flags().StringVarP(&output, "output", "o", "", "Output format (yaml|json|table)")
+	failCmd := &cobra.Command{
+		Use: "fail",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return errors.New("boom")
+		},
+	}
+	var name string
+	createCmd := &cobra.Command{
+		Use: "create",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	createCmd.Flags().StringVar(&name, "name", "", "Name of the resource")
+	_ = createCmd.MarkFlagRequired("name")
+	quietCmd := &cobra.Command{
+		Use:          "quiet",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	p.AddCommands(getCmd, failCmd, createCmd, quietCmd)
+	generateManifestCmd, _, err := p.Cmd.Find([]string{"generate-manifest"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stderr   string
+		// usage is the command whose usage is expected after the error
+		usage *cobra.Command
+	}{
+		{
+			name:     "typed error",
+			args:     []string{"get", "foo"},
+			exitCode: pluginerrors.ExitCodeNotFound,
+			stderr: `Error: resource "foo" not found
+Hint: Run 'test-plugin list' to list the resources.
+`,
+		},
+		{
+			name:     "typed error as json",
+			args:     []string{"get", "foo", "-o", "json"},
+			exitCode: pluginerrors.ExitCodeNotFound,
+			stderr: `{
+  "kind": "NotFound",
+  "message": "resource \"foo\" not found",
+  "hint": "Run 'test-plugin list' to list the resources.",
+  "exitCode": 3
+}
+`,
+		},
+		{
+			name:     "untyped error",
+			args:     []string{"fail"},
+			exitCode: pluginerrors.ExitCodeInternal,
+			stderr:   "Error: boom\n",
+		},
+		{
+			name:     "invalid arguments",
+			args:     []string{"get"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: accepts 1 arg(s), received 0
+Hint: Run 'test-plugin get --help' for usage.
+`,
+			usage: getCmd,
+		},
+		{
+			name:     "unknown flag",
+			args:     []string{"get", "foo", "--bar"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: unknown flag: --bar
+Hint: Run 'test-plugin get --help' for usage.
+`,
+			usage: getCmd,
+		},
+		{
+			name:     "invalid arguments as json",
+			args:     []string{"get", "-o", "json"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `{
+  "kind": "Usage",
+  "message": "accepts 1 arg(s), received 0",
+  "hint": "Run 'test-plugin get --help' for usage.",
+  "exitCode": 2
+}
+`,
+		},
+		{
+			name:     "missing required flag",
+			args:     []string{"create"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: required flag(s) "name" not set
+Hint: Run 'test-plugin create --help' for usage.
+`,
+			usage: createCmd,
+		},
+		{
+			name:     "one required flag group",
+			args:     []string{"generate-manifest"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: at least one of the flags in the group [image uri] is required
+Hint: Run 'test-plugin generate-manifest --help' for usage.
+`,
+			usage: generateManifestCmd,
+		},
+		{
+			name:     "mutually exclusive flag group",
+			args:     []string{"generate-manifest", "--image", "registry.example.com/plugins/test-plugin:v1.2.3", "--uri", "/tmp/plugins/test-plugin"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: if any flags in the group [image uri] are set none of the others can be; [image uri] were all set
+Hint: Run 'test-plugin generate-manifest --help' for usage.
+`,
+			usage: generateManifestCmd,
+		},
+		{
+			name:     "usage silenced by the command",
+			args:     []string{"quiet", "foo"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: unknown command "foo" for "test-plugin quiet"
+Hint: Run 'test-plugin quiet --help' for usage.
+`,
+		},
+		{
+			name:     "unknown command",
+			args:     []string{"delete"},
+			exitCode: pluginerrors.ExitCodeUsage,
+			stderr: `Error: unknown command "delete" for "test-plugin"
+Hint: Run 'test-plugin --help' for usage.
+`,
+			usage: p.Cmd,
+		},
+	}
+	for _, spec := range tests {
+		t.Run(spec.name, func(t *testing.T) {
+			output = ""
+			var stderr bytes.Buffer
+			p.Cmd.SetErr(&stderr)
+			p.Cmd.SetArgs(spec.args)
+			err := p.Execute()
+			require.Error(t, err)
+			assert.Equal(t, spec.exitCode, pluginerrors.ExitCode(err))
+			expected := spec.stderr
+			if spec.usage != nil {
+				expected += "\n" + spec.usage.UsageString()
+			}
+			assert.Equal(t, expected, stderr.String())
+		})
+	}
+}
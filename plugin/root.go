@@ -49,7 +49,10 @@ func newRootCmd(descriptor *PluginDescriptor) *cobra.Command {
 			"target":                           string(descriptor.Target),
 			cobra.CommandDisplayNameAnnotation: cmdName,
 		},
+		// Check the command is invoked with an active context of a supported context type
+		PersistentPreRunE: newCheckActiveContextFunc(descriptor),
 	}
+	cmd.SetFlagErrorFunc(flagErrorFunc)
 	cobra.AddTemplateFuncs(TemplateFuncs)
 	cmd.SetUsageTemplate(cmdTemplate)
 
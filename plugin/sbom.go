@@ -0,0 +1,227 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// goSumPropertyName is the name of the CycloneDX property of the go.sum checksum of the modules. The "h1:"
+	// checksum is a hash of the file tree of the module, not a hash of an artifact, so it is not reported as a
+	// hash of the component.
+	goSumPropertyName = "go.sum:h1"
+
+	// SBOMFormatCycloneDX is the format of the software bill of materials following the CycloneDX 1.5 JSON specification
+	SBOMFormatCycloneDX = "cyclonedx"
+	// SBOMFormatSPDX is the format of the software bill of materials following the SPDX 2.3 JSON specification
+	SBOMFormatSPDX = "spdx"
+)
+
+// cycloneDXSBOM is a CycloneDX software bill of materials of the Go modules of a plugin binary
+type cycloneDXSBOM struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// newCycloneDXSBOM returns the CycloneDX software bill of materials of the plugin binary
+func newCycloneDXSBOM(desc *PluginDescriptor, info *pluginBuildInfo) *cycloneDXSBOM {
+	plugin := cycloneDXComponent{
+		Type:    "application",
+		BOMRef:  "plugin:" + desc.Name,
+		Name:    desc.Name,
+		Version: desc.Version,
+	}
+	if info.MainModule != nil {
+		plugin.PURL = getModulePURL(info.MainModule)
+	}
+
+	sbom := &cycloneDXSBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.NewString(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Component: plugin,
+		},
+		Components: []cycloneDXComponent{},
+	}
+	dependsOn := []string{}
+	for _, dep := range info.Dependencies {
+		purl := getModulePURL(dep)
+		component := cycloneDXComponent{
+			Type:    "library",
+			BOMRef:  purl,
+			Name:    dep.Path,
+			Version: getModuleVersion(dep),
+			PURL:    purl,
+		}
+		if sum := getModuleGoSum(dep); sum != "" {
+			component.Properties = []cycloneDXProperty{{Name: goSumPropertyName, Value: sum}}
+		}
+		sbom.Components = append(sbom.Components, component)
+		dependsOn = append(dependsOn, purl)
+	}
+	sbom.Dependencies = []cycloneDXDependency{{Ref: plugin.BOMRef, DependsOn: dependsOn}}
+	return sbom
+}
+
+// spdxSBOM is a SPDX software bill of materials of the Go modules of a plugin binary
+type spdxSBOM struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// newSPDXSBOM returns the SPDX software bill of materials of the plugin binary
+func newSPDXSBOM(desc *PluginDescriptor, info *pluginBuildInfo) *spdxSBOM {
+	plugin := spdxPackage{
+		Name:             desc.Name,
+		SPDXID:           "SPDXRef-Package-plugin",
+		VersionInfo:      desc.Version,
+		DownloadLocation: "NOASSERTION",
+	}
+	if info.MainModule != nil {
+		plugin.ExternalRefs = []spdxExternalRef{newSPDXPURLRef(info.MainModule)}
+	}
+
+	creator := "Tool: tanzu-plugin-runtime"
+	if version := getPluginRuntimeVersion(); version != "" {
+		creator += "-" + version
+	}
+	sbom := &spdxSBOM{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              desc.Name,
+		DocumentNamespace: fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s-%s", desc.Name, desc.Version, uuid.NewString()),
+		CreationInfo: spdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{creator},
+		},
+		Packages: []spdxPackage{plugin},
+		Relationships: []spdxRelationship{
+			{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: plugin.SPDXID},
+		},
+	}
+	for i, dep := range info.Dependencies {
+		pkg := spdxPackage{
+			Name:             dep.Path,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      getModuleVersion(dep),
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs:     []spdxExternalRef{newSPDXPURLRef(dep)},
+		}
+		sbom.Packages = append(sbom.Packages, pkg)
+		sbom.Relationships = append(sbom.Relationships, spdxRelationship{
+			SPDXElementID:      plugin.SPDXID,
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: pkg.SPDXID,
+		})
+	}
+	return sbom
+}
+
+func newSPDXPURLRef(m *moduleInfo) spdxExternalRef {
+	return spdxExternalRef{
+		ReferenceCategory: "PACKAGE-MANAGER",
+		ReferenceType:     "purl",
+		ReferenceLocator:  getModulePURL(m),
+	}
+}
+
+// getModuleVersion returns the version of the module, the version of the replacement module if replaced
+func getModuleVersion(m *moduleInfo) string {
+	if m.Replace != nil && m.Replace.Version != "" {
+		return m.Replace.Version
+	}
+	return m.Version
+}
+
+// getModulePURL returns the package URL of the module e.g. pkg:golang/github.com/spf13/cobra@v1.8.0
+func getModulePURL(m *moduleInfo) string {
+	purl := "pkg:golang/" + m.Path
+	if version := getModuleVersion(m); version != "" && version != "(devel)" {
+		purl += "@" + version
+	}
+	return purl
+}
+
+// getModuleGoSum returns the "h1:" checksum of the module recorded in go.sum, empty if not available.
+// The SPDX packages don't report it, as SPDX has no checksum algorithm matching the go.sum directory hash.
+func getModuleGoSum(m *moduleInfo) string {
+	sum := m.Sum
+	if m.Replace != nil {
+		sum = m.Replace.Sum
+	}
+	if !strings.HasPrefix(sum, "h1:") {
+		return ""
+	}
+	return sum
+}
@@ -5,6 +5,7 @@ package plugin
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -58,8 +59,8 @@ func WithNoStderr() CommandOptions {
 	}
 }
 
-func runCommand(commandPath string, args []string, opts *cmdOptions) (bytes.Buffer, bytes.Buffer, error) {
-	command := exec.Command(commandPath, args...)
+func runCommand(ctx context.Context, commandPath string, args []string, opts *cmdOptions) (bytes.Buffer, bytes.Buffer, error) {
+	command := exec.CommandContext(ctx, commandPath, args...)
 
 	var stderr bytes.Buffer
 	var stdout bytes.Buffer
@@ -124,6 +125,8 @@ func SyncPluginsForTarget(target types.Target, opts ...CommandOptions) (string,
 //	var outBuf bytes.Buffer
 //	var errBuf bytes.Buffer
 //	SyncPluginsForContextType(types.ContextTypeK8s, WithOutputWriter(&outBuf), WithErrorWriter(&errBuf))
+//
+// Deprecated: SyncPluginsForContextType is deprecated. Use SyncPlugins instead
 func SyncPluginsForContextType(contextType types.ContextType, opts ...CommandOptions) (string, error) {
 	// For now, the implementation expects env var TANZU_BIN to be set and
 	// pointing to the core CLI binary used to invoke the plugin sync with.
@@ -138,6 +141,15 @@ func SyncPluginsForContextType(contextType types.ContextType, opts ...CommandOpt
 		return "", errors.New("the environment variable TANZU_BIN is not set")
 	}
 
+	args := getSyncPluginsArgs(context.Background(), cliPath, contextType)
+
+	// Runs the actual command
+	stdoutOutput, stderrOutput, err := runCommand(context.Background(), cliPath, args, options)
+	return fmt.Sprintf("%s%s", stdoutOutput.String(), stderrOutput.String()), err
+}
+
+// getSyncPluginsArgs returns the arguments of the CLI command syncing the plugins of the contextType
+func getSyncPluginsArgs(ctx context.Context, cliPath string, contextType types.ContextType) []string {
 	altCommandArgs := []string{customCommandName}
 	args := []string{"plugin", "sync"}
 
@@ -146,12 +158,9 @@ func SyncPluginsForContextType(contextType types.ContextType, opts ...CommandOpt
 
 	// Check if there is an alternate means to perform the plugin syncing
 	// operation, if not fall back to `plugin sync`
-	stdoutOutput, _, err := runCommand(cliPath, altCommandArgs, &cmdOptions{outWriter: io.Discard, errWriter: io.Discard})
+	stdoutOutput, _, err := runCommand(ctx, cliPath, altCommandArgs, &cmdOptions{outWriter: io.Discard, errWriter: io.Discard})
 	if err == nil && stdoutOutput.String() != "" {
 		args = strings.Fields(stdoutOutput.String())
 	}
-
-	// Runs the actual command
-	stdoutOutput, stderrOutput, err := runCommand(cliPath, args, options)
-	return fmt.Sprintf("%s%s", stdoutOutput.String(), stderrOutput.String()), err
+	return args
 }
@@ -0,0 +1,171 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+const (
+	// telemetryDirName is the name of the telemetry directory in the state directory
+	telemetryDirName = "telemetry"
+	// telemetryEventsFileName is the name of the JSONL file of the default telemetry sink
+	telemetryEventsFileName = "plugin-events.jsonl"
+	// maxTelemetryFileSize is the size from which the JSONL file of the telemetry events is rotated
+	maxTelemetryFileSize = 5 * 1024 * 1024
+)
+
+// CommandEvent is the telemetry event recorded for an invocation of the plugin.
+// The event never includes the values of the arguments or of the flags.
+type CommandEvent struct {
+	// Timestamp is the time the command was invoked
+	Timestamp time.Time `json:"timestamp"`
+	// CliID is the uuid uniquely identifying the CLI instance
+	CliID string `json:"cliId,omitempty"`
+	// Plugin is the name of the plugin
+	Plugin string `json:"plugin"`
+	// Version is the version of the plugin
+	Version string `json:"version"`
+	// Target is the target of the plugin
+	Target types.Target `json:"target,omitempty"`
+	// CommandPath is the path of the command invoked e.g. "cluster list"
+	CommandPath string `json:"commandPath"`
+	// Flags are the names of the flags specified with the command
+	Flags []string `json:"flags,omitempty"`
+	// DurationMs is the duration of the command in milliseconds
+	DurationMs int64 `json:"durationMs"`
+	// ExitCode is the exit code of the command, see the plugin/errors package
+	ExitCode int `json:"exitCode"`
+	// ErrorKind is the class of the error of the command, empty if the command succeeded
+	ErrorKind pluginerrors.Kind `json:"errorKind,omitempty"`
+	// ErrorCode is the code of the error of the command, empty if the command succeeded or the error has no code
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+// TelemetrySink records the telemetry events of the plugin
+type TelemetrySink interface {
+	// Record records the event
+	Record(event *CommandEvent) error
+}
+
+// jsonlTelemetrySink records the telemetry events as JSON lines appended to a file
+type jsonlTelemetrySink struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+}
+
+// NewJSONLTelemetrySink returns the TelemetrySink appending the events as JSON lines to the file.
+// The file and its directory are created if missing. The file is rotated to <path>.1 when it exceeds 5 MiB,
+// replacing the previously rotated file.
+func NewJSONLTelemetrySink(path string) TelemetrySink {
+	return &jsonlTelemetrySink{path: path, maxSize: maxTelemetryFileSize}
+}
+
+// Record appends the event to the file, rotating the file first if the event makes it exceed the max size
+func (s *jsonlTelemetrySink) Record(event *CommandEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the telemetry event")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return errors.Wrap(err, "failed to create the telemetry directory")
+	}
+	if info, err := os.Stat(s.path); err == nil && info.Size() > 0 && info.Size()+int64(len(b))+1 > s.maxSize {
+		if err := os.Rename(s.path, s.path+".1"); err != nil {
+			return errors.Wrap(err, "failed to rotate the telemetry events file")
+		}
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the telemetry events file")
+	}
+	defer f.Close()
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write the telemetry event")
+	}
+	return nil
+}
+
+// EnableTelemetry enables the recording of a CommandEvent per invocation of the plugin with Execute.
+// The events are only recorded if the user opted in the CEIP. The events are recorded with the sink,
+// or appended to <state dir>/telemetry/plugin-events.jsonl if the sink is nil.
+func (p *Plugin) EnableTelemetry(sink TelemetrySink) {
+	if sink == nil {
+		sink = &defaultTelemetrySink{}
+	}
+	p.telemetrySink = sink
+}
+
+// defaultTelemetrySink records the events in the JSONL file of the state directory, resolved when recording
+type defaultTelemetrySink struct{}
+
+// Record appends the event to the JSONL file of the state directory
+func (s *defaultTelemetrySink) Record(event *CommandEvent) error {
+	stateDir, err := config.LocalStateDir()
+	if err != nil {
+		return err
+	}
+	return NewJSONLTelemetrySink(filepath.Join(stateDir, telemetryDirName, telemetryEventsFileName)).Record(event)
+}
+
+// recordTelemetryEvent records the event of the invocation of the command if the telemetry is enabled and the
+// user opted in the CEIP. The failures are logged and do not fail the command.
+func (p *Plugin) recordTelemetryEvent(cmd *cobra.Command, err *pluginerrors.Error, start time.Time) {
+	if p.telemetrySink == nil || cmd == nil || isCobraCommand(cmd) || !isCEIPOptedIn() {
+		return
+	}
+	event := newCommandEvent(p.descriptor, cmd, err, start)
+	if recordErr := p.telemetrySink.Record(event); recordErr != nil {
+		log.V(6).Infof("failed to record the telemetry event of the command %q: %v", cmd.CommandPath(), recordErr)
+	}
+}
+
+// newCommandEvent returns the telemetry event of the invocation of the command
+func newCommandEvent(desc *PluginDescriptor, cmd *cobra.Command, err *pluginerrors.Error, start time.Time) *CommandEvent {
+	event := &CommandEvent{
+		Timestamp:   start.UTC(),
+		Plugin:      cmd.Root().Name(),
+		CommandPath: strings.TrimPrefix(strings.TrimPrefix(cmd.CommandPath(), cmd.Root().CommandPath()), " "),
+		DurationMs:  time.Since(start).Milliseconds(),
+		ExitCode:    pluginerrors.ExitCodeOK,
+	}
+	if desc != nil {
+		event.Plugin, event.Version, event.Target = desc.Name, desc.Version, desc.Target
+	}
+	if cliID, idErr := config.GetCLIId(); idErr == nil {
+		event.CliID = cliID
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		event.Flags = append(event.Flags, f.Name)
+	})
+	if err != nil {
+		event.ExitCode = err.ExitCode()
+		event.ErrorKind = err.Kind
+		event.ErrorCode = err.Code
+	}
+	return event
+}
+
+// isCEIPOptedIn returns whether the user opted in the CEIP
+func isCEIPOptedIn() bool {
+	optIn, err := config.GetCEIPOptIn()
+	return err == nil && strings.EqualFold(optIn, "true")
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 VMware, Inc. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config"
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
+)
+
+// fakeTelemetrySink keeps the telemetry events in memory
+type fakeTelemetrySink struct {
+	events []*CommandEvent
+}
+
+func (s *fakeTelemetrySink) Record(event *CommandEvent) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func newTelemetryTestPlugin(t *testing.T) *Plugin {
+	p, err := NewPlugin(&PluginDescriptor{
+		Name:        "test-plugin",
+		Target:      types.TargetK8s,
+		Description: "Description of the plugin",
+		Version:     "v1.2.3",
+		Group:       ManageCmdGroup,
+	})
+	require.NoError(t, err)
+	clusterCmd := &cobra.Command{Use: "cluster"}
+	listCmd := &cobra.Command{
+		Use:  "list",
+		RunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	listCmd.Flags().StringP("output", "o", "table", "Output format")
+	listCmd.Flags().String("namespace", "", "Namespace of the clusters")
+	getCmd := &cobra.Command{
+		Use: "get",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pluginerrors.NewNotFoundError("cluster not found", pluginerrors.WithCode("CLUSTER_NOT_FOUND"))
+		},
+	}
+	deleteCmd := &cobra.Command{
+		Use:  "delete",
+		RunE: func(cmd *cobra.Command, args []string) error { return errors.New("boom") },
+	}
+	upgradeCmd := &cobra.Command{
+		Use: "upgrade",
+		Run: func(cmd *cobra.Command, args []string) { panic("nil cluster") },
+	}
+	clusterCmd.AddCommand(listCmd, getCmd, deleteCmd, upgradeCmd)
+	p.AddCommands(clusterCmd)
+	p.Cmd.SetOut(io.Discard)
+	p.Cmd.SetErr(io.Discard)
+	return p
+}
+
+func TestTelemetry(t *testing.T) {
+	setupSandboxedConfig(t, `cli:
+  ceipOptIn: "true"
+  cliId: 0a1b2c3d-cli
+`)
+	sink := &fakeTelemetrySink{}
+	p := newTelemetryTestPlugin(t)
+	p.EnableTelemetry(sink)
+
+	p.Cmd.SetArgs([]string{"cluster", "list", "--namespace", "secret-namespace", "-o", "json"})
+	require.NoError(t, p.Execute())
+	p.Cmd.SetArgs([]string{"cluster", "get", "prod"})
+	require.Error(t, p.Execute())
+	p.Cmd.SetArgs([]string{"cluster", "delete", "prod"})
+	require.Error(t, p.Execute())
+	t.Setenv(config.EnvStateDirKey, t.TempDir())
+	p.Cmd.SetArgs([]string{"cluster", "upgrade"})
+	require.Error(t, p.Execute())
+	p.Cmd.SetArgs([]string{"help", "cluster"})
+	require.NoError(t, p.Execute())
+
+	require.Len(t, sink.events, 4)
+	for _, event := range sink.events {
+		assert.Equal(t, "0a1b2c3d-cli", event.CliID)
+		assert.Equal(t, "test-plugin", event.Plugin)
+		assert.Equal(t, "v1.2.3", event.Version)
+		assert.Equal(t, types.TargetK8s, event.Target)
+		assert.False(t, event.Timestamp.IsZero())
+		assert.GreaterOrEqual(t, event.DurationMs, int64(0))
+	}
+	assert.Equal(t, "cluster list", sink.events[0].CommandPath)
+	assert.Equal(t, []string{"namespace", "output"}, sink.events[0].Flags)
+	assert.Equal(t, pluginerrors.ExitCodeOK, sink.events[0].ExitCode)
+	assert.Empty(t, sink.events[0].ErrorKind)
+
+	assert.Equal(t, "cluster get", sink.events[1].CommandPath)
+	assert.Equal(t, pluginerrors.ExitCodeNotFound, sink.events[1].ExitCode)
+	assert.Equal(t, pluginerrors.KindNotFound, sink.events[1].ErrorKind)
+	assert.Equal(t, "CLUSTER_NOT_FOUND", sink.events[1].ErrorCode)
+
+	assert.Equal(t, "cluster delete", sink.events[2].CommandPath)
+	assert.Equal(t, pluginerrors.ExitCodeInternal, sink.events[2].ExitCode)
+	assert.Equal(t, pluginerrors.KindInternal, sink.events[2].ErrorKind)
+
+	// The panics are recorded for the command which panicked
+	assert.Equal(t, "cluster upgrade", sink.events[3].CommandPath)
+	assert.Equal(t, pluginerrors.KindInternal, sink.events[3].ErrorKind)
+	assert.Equal(t, ErrCodePluginCrashed, sink.events[3].ErrorCode)
+
+	b, err := json.Marshal(sink.events)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "secret-namespace")
+}
+
+func TestTelemetryCEIPOptOut(t *testing.T) {
+	setupSandboxedConfig(t, `cli:
+  ceipOptIn: "false"
+`)
+	sink := &fakeTelemetrySink{}
+	p := newTelemetryTestPlugin(t)
+	p.EnableTelemetry(sink)
+
+	p.Cmd.SetArgs([]string{"cluster", "list"})
+	require.NoError(t, p.Execute())
+	assert.Empty(t, sink.events)
+}
+
+func TestTelemetryDefaultSink(t *testing.T) {
+	setupSandboxedConfig(t, `cli:
+  ceipOptIn: "true"
+`)
+	stateDir := t.TempDir()
+	t.Setenv(config.EnvStateDirKey, stateDir)
+	p := newTelemetryTestPlugin(t)
+	p.EnableTelemetry(nil)
+
+	for i := 0; i < 2; i++ {
+		p.Cmd.SetArgs([]string{"cluster", "list"})
+		require.NoError(t, p.Execute())
+	}
+
+	f, err := os.Open(filepath.Join(stateDir, "telemetry", "plugin-events.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+	var events []CommandEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event CommandEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 2)
+	assert.Equal(t, "cluster list", events[1].CommandPath)
+}
+
+func TestJSONLTelemetrySinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := &jsonlTelemetrySink{path: path, maxSize: 200}
+
+	for _, commandPath := range []string{"cluster list", "cluster get", "cluster delete"} {
+		require.NoError(t, sink.Record(&CommandEvent{Plugin: "test-plugin", CommandPath: commandPath}))
+	}
+
+	// The file exceeding the max size is rotated, replacing the previously rotated file
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(current), 200)
+	assert.Contains(t, string(current), `"commandPath":"cluster delete"`)
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Contains(t, string(rotated), `"commandPath":"cluster get"`)
+	assert.NotContains(t, string(rotated)+string(current), `"commandPath":"cluster list"`)
+}
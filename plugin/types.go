@@ -3,7 +3,11 @@
 
 package plugin
 
-import "github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+import (
+	"context"
+
+	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
+)
 
 // CmdGroup is a group of CLI commands.
 type CmdGroup string
@@ -14,6 +18,12 @@ type PluginCompletionType int
 // Hook is the mechanism used to define function for plugin hooks
 type Hook func() error
 
+// LifecycleHook is the mechanism used to define function for plugin lifecycle hooks. The hook receives the
+// input provided by the CLI and returns its result. The context is cancelled when the hook times out and the
+// hook should return when the context is done: a hook ignoring the context keeps running in the background
+// until the plugin exits, after its timeout is reported.
+type LifecycleHook func(ctx context.Context, input *HookInput) (*HookResult, error)
+
 const (
 	// NativePluginCompletion indicates command line completion is determined using the built in
 	// cobra.Command __complete mechanism.
@@ -102,9 +112,25 @@ type PluginDescriptor struct {
 	// PostInstallHook is function to be run post install of a plugin.
 	PostInstallHook Hook `json:"-" yaml:"-"`
 
+	// PreUninstallHook is function to be run before the uninstall of a plugin.
+	PreUninstallHook LifecycleHook `json:"-" yaml:"-"`
+
+	// PostUpgradeHook is function to be run post upgrade of a plugin.
+	// The version of the plugin before the upgrade is provided with HookInput.PreviousVersion.
+	PostUpgradeHook LifecycleHook `json:"-" yaml:"-"`
+
+	// ContextChangedHook is function to be run when the active context changes e.g. to update the
+	// kubeconfig entries of the plugin. The context is provided with HookInput.Context.
+	ContextChangedHook LifecycleHook `json:"-" yaml:"-"`
+
 	// DefaultFeatureFlags is default featureflags to be configured if missing when invoking plugin
 	DefaultFeatureFlags map[string]bool `json:"defaultFeatureFlags,omitempty" yaml:"defaultFeatureFlags,omitempty"`
 
+	// FeatureFlags declares the typed feature flags of the plugin along with their metadata.
+	// The feature flags specified with DefaultFeatureFlags are declared as bool feature flags
+	// unless explicitly declared here.
+	FeatureFlags []types.FeatureFlagSpec `json:"featureFlags,omitempty" yaml:"featureFlags,omitempty"`
+
 	// InvokedAs provides a specific mapping to how any command provided by this plugin should be invoked as.
 	// If unset (which is equivalent to setting it to ["<PluginDescriptor.Name>"]), commands will typically be invocable
 	// with the Tanzu CLI using "<PluginDescriptor.Name> <command name> commandargs...."
@@ -119,4 +145,12 @@ type PluginDescriptor struct {
 	// When unset, the plugin does not define any specific opinions on this aspect.
 	// EXPERIMENTAL: subject to change prior to the next official minor release
 	SupportedContextType []types.ContextType `json:"supportedContextType,omitempty" yaml:"supportedContextType,omitempty"`
+
+	// ContextRequired specifies whether the commands of the plugin require an active context of one of their
+	// supported context types. The ContextRequiredAnnotation of the commands takes precedence.
+	ContextRequired bool `json:"contextRequired,omitempty" yaml:"contextRequired,omitempty"`
+
+	// ContextOverride specifies whether the commands of the plugin accept the persistent `--context` flag and the
+	// TANZU_CONTEXT environment variable overriding the active context for a single invocation.
+	ContextOverride bool `json:"contextOverride,omitempty" yaml:"contextOverride,omitempty"`
 }
@@ -52,6 +52,11 @@ const CmdTemplate = `{{ bold "Usage:" }}
 // cmdTemplate is the template for plugin commands.
 const cmdTemplate = `{{ printHelp . }}`
 
+// cliCommandAnnotation marks the hidden commands of the runtime invoked by the CLI e.g. the lifecycle hooks.
+// These commands are ignored by the padding of the sub-commands in the help, so adding them doesn't change
+// the help of the plugins.
+const cliCommandAnnotation = "cliCommand"
+
 // Constants for help text labels
 const (
 	usageStr                = "Usage:"
@@ -160,7 +165,7 @@ func printHelp(cmd *cobra.Command) string {
 		output.WriteString("\n" + component.Bold(availableCommandsStr) + "\n")
 		for _, c := range cmd.Commands() {
 			if c.IsAvailableCommand() {
-				output.WriteString(indentStr + component.Rpad(c.Name(), c.NamePadding()) + " " + c.Short + "\n")
+				output.WriteString(indentStr + component.Rpad(c.Name(), namePadding(cmd)) + " " + c.Short + "\n")
 			}
 		}
 	}
@@ -179,7 +184,7 @@ func printHelp(cmd *cobra.Command) string {
 		output.WriteString("\n" + component.Bold(additionalHelpTopicsStr) + "\n")
 		for _, c := range cmd.Commands() {
 			if c.IsAdditionalHelpTopicCommand() {
-				output.WriteString(indentStr + component.Rpad(c.CommandPath(), c.CommandPathPadding()) + " " + c.Short + "\n")
+				output.WriteString(indentStr + component.Rpad(c.CommandPath(), commandPathPadding(cmd)) + " " + c.Short + "\n")
 			}
 		}
 	}
@@ -188,6 +193,38 @@ func printHelp(cmd *cobra.Command) string {
 	return output.String()
 }
 
+// minPadding is the min padding of the names and the paths of the sub-commands, as in cobra
+const minPadding = 11
+
+// namePadding returns the padding of the names of the sub-commands of the command. Like cobra, the padding
+// is computed from all the sub-commands, except the hidden commands invoked by the CLI (see cliCommandAnnotation).
+func namePadding(cmd *cobra.Command) int {
+	padding := minPadding
+	for _, c := range cmd.Commands() {
+		if !isCLICommand(c) && len(c.Name()) > padding {
+			padding = len(c.Name())
+		}
+	}
+	return padding
+}
+
+// commandPathPadding returns the padding of the paths of the sub-commands of the command, ignoring the
+// hidden commands invoked by the CLI like namePadding
+func commandPathPadding(cmd *cobra.Command) int {
+	padding := minPadding
+	for _, c := range cmd.Commands() {
+		if !isCLICommand(c) && len(c.CommandPath()) > padding {
+			padding = len(c.CommandPath())
+		}
+	}
+	return padding
+}
+
+// isCLICommand returns true if the command is a hidden command of the runtime invoked by the CLI
+func isCLICommand(cmd *cobra.Command) bool {
+	return cmd.Hidden && cmd.Annotations[cliCommandAnnotation] == "true"
+}
+
 // TemplateFuncs are the template usage funcs.
 var TemplateFuncs = template.FuncMap{
 	"printHelp": printHelp,
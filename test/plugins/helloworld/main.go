@@ -5,11 +5,13 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/config/types"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/log"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin"
 	"github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/buildinfo"
+	pluginerrors "github.com/vmware-tanzu/tanzu-plugin-runtime/plugin/errors"
 )
 
 var descriptor = plugin.PluginDescriptor{
@@ -33,7 +35,7 @@ func main() {
 	)
 
 	if err := p.Execute(); err != nil {
-		log.Fatal(err, "")
+		os.Exit(pluginerrors.ExitCode(err))
 	}
 }
 